@@ -2,56 +2,224 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	
 	"fmt"
 	"log"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
 	application "github.com/justinwongcn/bazi-mcp/internal/application"
 	baziDomain "github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+	calendarDomain "github.com/justinwongcn/bazi-mcp/internal/domain/calendar"
 	
 	baziInfra "github.com/justinwongcn/bazi-mcp/internal/infrastructure/bazi"
+	baziCache "github.com/justinwongcn/bazi-mcp/internal/infrastructure/bazi/cache"
+	baziFixture "github.com/justinwongcn/bazi-mcp/internal/infrastructure/bazi/fixture"
+	baziLocal "github.com/justinwongcn/bazi-mcp/internal/infrastructure/bazi/local"
+	baziProvider "github.com/justinwongcn/bazi-mcp/internal/infrastructure/bazi/provider"
+	"github.com/justinwongcn/bazi-mcp/internal/infrastructure/config"
+	locationInfra "github.com/justinwongcn/bazi-mcp/internal/infrastructure/location"
 
 	"github.com/ThinkInAIXYZ/go-mcp/protocol"
 	"github.com/ThinkInAIXYZ/go-mcp/server"
-	"github.com/ThinkInAIXYZ/go-mcp/transport"
 )
 
 // 2. 将工具名称定义为领域常量（提升领域概念内聚性）
 const (
-	BaziToolName = "bazi_paipan" // 领域工具名称常量定义
+	BaziToolName     = "bazi_paipan"      // 领域工具名称常量定义
+	CalendarToolName = "calendar_convert" // 公历农历互转工具名称
+	CompareToolName  = "bazi_compare"     // 跨提供方排盘比对工具名称
+	StatusToolName   = "server_status"    // 服务器运行状态工具名称
+	AnalyzeToolName  = "bazi_analyze"     // 命局分析工具名称
+	ElementsToolName = "bazi_wuxing"      // 五行分布工具名称
+
+	ProvincesResourceURI   = "data://provinces"                         // 省份列表资源
+	CitiesResourceTemplate = application.CitiesURIPrefix + "{province}" // 城市列表资源模板
 )
 
 // Init 初始化并启动八字排盘MCP服务器。
 func Init() error {
-	// 1. 初始化依赖
-	baziAppService := setupDependencies()
+	// 1. 读取配置并初始化依赖
+	cfg, err := config.Load(os.Args[1:], config.Default(transport), os.Getenv)
+	if err != nil {
+		return err
+	}
+	baziAppService, statusAppService, err := setupDependencies(cfg)
+	if err != nil {
+		return err
+	}
+	if cfg.HealthCheck {
+		if err := checkProviders(statusAppService); err != nil {
+			return err
+		}
+	}
 
 	// 2. 创建并配置服务器
-	mcpServer, err := createAndConfigureServer(baziAppService)
+	mcpServer, err := createAndConfigureServer(baziAppService, statusAppService, cfg.Transport)
 	if err != nil {
 		return err
 	}
 
 	// 3. 运行服务器
-	return runServer(mcpServer)
+	return runServer(mcpServer, cfg.Transport)
 }
 
-// setupDependencies 初始化应用依赖
-func setupDependencies() *application.BaziAppService {
-	apiClient := baziInfra.NewAPIClient()
-	var baziDomainService baziDomain.Service = apiClient
-	return application.NewBaziAppService(baziDomainService)
+// setupDependencies 按配置初始化应用依赖：排盘提供方及其优先级、跨提供方比对、排盘缓存、请求默认值与运行状态
+func setupDependencies(cfg config.Config) (*application.BaziAppService, *application.StatusAppService, error) {
+	gazetteer, err := locationInfra.LoadGazetteer()
+	if err != nil {
+		return nil, nil, fmt.Errorf("加载地名录失败: %w", err)
+	}
+	worldGazetteer, err := locationInfra.LoadWorldGazetteer()
+	if err != nil {
+		return nil, nil, fmt.Errorf("加载境外城市地名录失败: %w", err)
+	}
+
+	specs := make([]baziProvider.Spec, 0, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		specs = append(specs, baziProvider.Spec{Name: p.Name, Priority: p.Priority})
+	}
+	registry := newProviderRegistry(cfg)
+	fallback, err := registry.Build(specs)
+	if err != nil {
+		return nil, nil, err
+	}
+	compare, err := compareProviders(registry, fallback.Providers(), cfg.Compare)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, p := range fallback.Providers() {
+		log.Printf("排盘提供方：%s（优先级 %d）\n", p.Name, p.Priority)
+	}
+	var baziDomainService baziDomain.Service = fallback
+	statusAppService := &application.StatusAppService{
+		Version:   versionInfo(),
+		Transport: describeTransport(cfg.Transport),
+		StartedAt: time.Now(),
+		Providers: fallback,
+	}
+
+	if cfg.Cache.Size > 0 {
+		cacheOpts := baziCache.Options{Size: cfg.Cache.Size, TTL: cfg.Cache.TTL, Dir: cfg.Cache.Dir, DiskSize: cfg.Cache.DiskSize}
+		cached, err := baziCache.New(baziDomainService, cacheOpts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("初始化排盘缓存失败: %w", err)
+		}
+		log.Printf("启用排盘缓存：内存 %d 条，有效期 %s，磁盘目录 %q\n", cacheOpts.Size, cacheOpts.TTL, cacheOpts.Dir)
+		baziDomainService = cached
+		statusAppService.CacheStats = cacheStatus(cached)
+	}
+	baziAppService := application.NewBaziAppService(baziDomainService, gazetteer, worldGazetteer)
+	baziAppService.CompareProviders = compare
+	baziAppService.Defaults = baziDomain.RequestDefaults{Sect: cfg.Defaults.Sect, Zhen: cfg.Defaults.Zhen, Lang: cfg.Defaults.Lang}
+	for _, school := range cfg.Analysis.Schools {
+		baziAppService.Schools = append(baziAppService.Schools, baziDomain.School(school))
+	}
+	return baziAppService, statusAppService, nil
+}
+
+// 排盘提供方名称
+const (
+	ProviderAPI     = baziInfra.ProviderName // 缘分居 API
+	ProviderLocal   = "local"                // 本地排盘引擎
+	ProviderFixture = "fixture"              // 录制结果，目录由 fixture.dir 指定
+)
+
+// newProviderRegistry 注册全部排盘提供方，提供方在被选用时才创建
+func newProviderRegistry(cfg config.Config) *baziProvider.Registry {
+	registry := baziProvider.NewRegistry()
+	registry.Register(ProviderAPI, func() (baziDomain.Service, error) {
+		client := baziInfra.NewAPIClient(clientOptions(cfg.API))
+		if dir := cfg.Fixture.RecordDir; dir != "" {
+			log.Printf("录制接口返回的排盘结果到 %s\n", dir)
+			return baziFixture.NewRecorder(client, dir)
+		}
+		return client, nil
+	})
+	registry.Register(ProviderLocal, func() (baziDomain.Service, error) {
+		return baziLocal.NewEngine(), nil
+	})
+	registry.Register(ProviderFixture, func() (baziDomain.Service, error) {
+		if cfg.Fixture.Dir == "" {
+			return nil, fmt.Errorf("需要通过 fixture.dir 或 BAZI_FIXTURE_DIR 指定录制结果目录")
+		}
+		return baziFixture.New(cfg.Fixture.Dir)
+	})
+	return registry
+}
+
+// compareProviders 返回跨提供方比对使用的两个提供方：由 names 指定，
+// 未指定时取优先级最高的两个已配置提供方，不足两个时不提供比对。已配置的提供方直接复用，其余按名称创建
+func compareProviders(registry *baziProvider.Registry, configured []baziProvider.Provider, names []string) ([]application.NamedService, error) {
+	if len(names) == 0 {
+		if len(configured) < 2 {
+			return nil, nil
+		}
+		return []application.NamedService{
+			{Name: configured[0].Name, Service: configured[0].Service},
+			{Name: configured[1].Name, Service: configured[1].Service},
+		}, nil
+	}
+
+	compare := make([]application.NamedService, 0, len(names))
+	for _, name := range names {
+		var service baziDomain.Service
+		for _, p := range configured {
+			if p.Name == name {
+				service = p.Service
+			}
+		}
+		if service == nil {
+			var err error
+			if service, err = registry.New(name); err != nil {
+				return nil, err
+			}
+		}
+		compare = append(compare, application.NamedService{Name: name, Service: service})
+	}
+	return compare, nil
+}
+
+// clientOptions 将接口配置转换为 APIClient 的配置，重试次数与熔断阈值为 0 时分别表示不重试、不熔断
+func clientOptions(api config.API) baziInfra.ClientOptions {
+	opts := baziInfra.ClientOptions{
+		Endpoint:         api.Endpoint,
+		APIKey:           api.Key,
+		Timeout:          api.Timeout,
+		MaxRetries:       api.Retries,
+		FailureThreshold: api.BreakerThreshold,
+		Cooldown:         api.BreakerCooldown,
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = -1
+	}
+	if opts.FailureThreshold == 0 {
+		opts.FailureThreshold = -1
+	}
+	return opts
 }
 
 // createAndConfigureServer 创建并配置MCP服务器
-func createAndConfigureServer(baziAppService *application.BaziAppService) (*server.Server, error) {
-	transportServer := transport.NewStdioServerTransport()
+func createAndConfigureServer(baziAppService *application.BaziAppService, statusAppService *application.StatusAppService, cfg config.Transport) (*server.Server, error) {
+	transportServer, err := newServerTransport(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("创建传输失败: %w", err)
+	}
 	mcpServer, err := server.NewServer(transportServer)
 	if err != nil {
 		return nil, fmt.Errorf("创建MCP服务器失败: %w", err)
 	}
 
-	if err := registerAllResources(mcpServer, baziAppService); err != nil {
+	if err := registerAllResources(mcpServer, baziAppService, statusAppService); err != nil {
 		return nil, fmt.Errorf("服务器配置失败: %w", err)
 	}
 
@@ -59,18 +227,41 @@ func createAndConfigureServer(baziAppService *application.BaziAppService) (*serv
 }
 
 // registerAllResources 注册所有资源
-func registerAllResources(mcpServer *server.Server, baziAppService *application.BaziAppService) error {
-	
+func registerAllResources(mcpServer *server.Server, baziAppService *application.BaziAppService, statusAppService *application.StatusAppService) error {
+	if err := registerLocationResources(mcpServer, application.NewLocationAppService(baziAppService.Gazetteer)); err != nil {
+		return err
+	}
 
+	registerChartSchemaResource(mcpServer)
 	registerBaziTool(mcpServer, baziAppService)
-	registerPrompts(mcpServer)
+	registerAnalyzeTool(mcpServer, baziAppService)
+	registerElementsTool(mcpServer, baziAppService)
+	if len(baziAppService.CompareProviders) >= 2 {
+		registerCompareTool(mcpServer, baziAppService)
+	}
+	registerCalendarTool(mcpServer, application.NewCalendarAppService())
+	registerStatusTool(mcpServer, statusAppService)
+	registerPrompts(mcpServer, baziAppService)
 	return nil
 }
 
-// runServer 启动服务器运行
-func runServer(mcpServer *server.Server) error {
-	log.Printf("八字排盘MCP服务器已启动，使用 stdio 模式进行通信\n")
-	if err := mcpServer.Run(); err != nil {
+// runServer 启动服务器运行，HTTP 模式下收到 SIGINT、SIGTERM 时优雅关闭
+func runServer(mcpServer *server.Server, cfg config.Transport) error {
+	log.Printf("八字排盘MCP服务器已启动：%s\n", describeTransport(cfg))
+	if cfg.Mode != config.TransportStdio {
+		go func() {
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+			<-signals
+			log.Printf("正在关闭服务器\n")
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := mcpServer.Shutdown(ctx); err != nil {
+				log.Printf("关闭服务器失败: %v", err)
+			}
+		}()
+	}
+	if err := mcpServer.Run(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("服务器运行失败: %w", err)
 	}
 	return nil
@@ -106,7 +297,7 @@ func registerBaziTool(mcpServer *server.Server, baziAppService *application.Bazi
 		}
 
 		// 2. 调用应用服务处理请求
-		resultText, isAppError, appErr := baziAppService.GetBaziPaipan(ctx, baziReq)
+		result, appErr := baziAppService.Paipan(ctx, baziReq)
 
 		// 3. 处理应用服务返回的结果
 		if appErr != nil {
@@ -115,31 +306,351 @@ func registerBaziTool(mcpServer *server.Server, baziAppService *application.Bazi
 			return protocol.NewCallToolResult([]protocol.Content{
 				&protocol.TextContent{
 					Type: "text",
-					Text: "处理请求时发生内部错误，请稍后再试或联系管理员。",
+					Text: providerErrorText(appErr),
 				},
 			}, true), nil // 标记为错误
 		}
 
-		// 应用服务正常处理完成，根据 isAppError 判断是成功还是业务错误
+		// 业务错误只有文本说明；排盘成功时按 output 返回文本、JSON 或两者。
+		// 当前协议版本不支持 structuredContent，JSON 以独立的文本内容返回
+		var contents []protocol.Content
+		if result.IsError || baziReq.Output != baziDomain.OutputJSON {
+			contents = append(contents, &protocol.TextContent{Type: "text", Text: result.Text})
+		}
+		if !result.IsError && (baziReq.Output == baziDomain.OutputJSON || baziReq.Output == baziDomain.OutputBoth) {
+			chartJSON, err := result.JSON()
+			if err != nil {
+				log.Printf("编码排盘结果失败: %v", err)
+				return protocol.NewCallToolResult([]protocol.Content{
+					&protocol.TextContent{Type: "text", Text: "处理请求时发生内部错误，请稍后再试或联系管理员。"},
+				}, true), nil
+			}
+			contents = append(contents, &protocol.TextContent{Type: "text", Text: chartJSON})
+		}
+		return protocol.NewCallToolResult(contents, result.IsError), nil // 根据应用服务的结果设置 IsError 标志
+	})
+}
+
+// registerAnalyzeTool 注册命局分析工具：排盘后在本地分析日主强弱、五行分布、格局、用神喜忌与刑冲合害，按 output 返回文本、JSON 或两者
+func registerAnalyzeTool(mcpServer *server.Server, baziAppService *application.BaziAppService) {
+	tool, err := newAnalyzeTool("根据生辰八字分析命局：按得令、得地、得势为日主强弱评分并给出各项明细，统计原局五行分布，判定正格与化气、专旺、从格并给出依据与可信度，按扶抑、调候或通关取用神、喜神、忌神、仇神并给出推导步骤，列出原局及 target_year（默认当年）所行大运、流年与原局之间的合冲刑害破")
+	if err != nil {
+		log.Fatalf("创建工具失败: %v", err)
+	}
+
+	mcpServer.RegisterTool(tool, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		// 排盘参数按 bazi.Request 的输入模式校验，target_year 再由 encoding/json 连同嵌入字段一并解析
+		var analyzeReq baziDomain.AnalyzeRequest
+		err := protocol.VerifyAndUnmarshal(req.RawArguments, &analyzeReq.Request)
+		if err == nil {
+			err = json.Unmarshal(req.RawArguments, &analyzeReq)
+		}
+		if err != nil {
+			return protocol.NewCallToolResult([]protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf("参数格式错误: %v\n请检查您的输入是否符合工具要求。", err),
+				},
+			}, true), nil
+		}
+
+		result, appErr := baziAppService.Analyze(ctx, analyzeReq)
+		if appErr != nil {
+			log.Printf("处理命局分析请求时发生内部错误: %v", appErr)
+			return protocol.NewCallToolResult([]protocol.Content{
+				&protocol.TextContent{Type: "text", Text: providerErrorText(appErr)},
+			}, true), nil
+		}
+
+		return analysisResult(analyzeReq.Output, result.Text, result.IsError, func() (string, error) { return result.Analysis.JSON() }), nil
+	})
+}
+
+// newAnalyzeTool 创建命局分析工具。go-mcp 生成输入模式时不展开嵌入字段，
+// 这里将 AnalyzeRequest 嵌入的排盘参数并入顶层，与 encoding/json 的解析方式一致
+func newAnalyzeTool(description string) (*protocol.Tool, error) {
+	tool, err := protocol.NewTool(AnalyzeToolName, description, baziDomain.AnalyzeRequest{})
+	if err != nil {
+		return nil, err
+	}
+	paipan, err := protocol.NewTool(AnalyzeToolName, description, baziDomain.Request{})
+	if err != nil {
+		return nil, err
+	}
+
+	// 输入模式由 go-mcp 缓存共享，合并到新的属性表与必填列表，不修改缓存内容
+	properties := maps.Clone(paipan.InputSchema.Properties)
+	required := slices.Clone(paipan.InputSchema.Required)
+	for name, property := range tool.InputSchema.Properties {
+		if name != "Request" {
+			properties[name] = property
+		}
+	}
+	for _, name := range tool.InputSchema.Required {
+		if name != "Request" {
+			required = append(required, name)
+		}
+	}
+	tool.InputSchema.Properties, tool.InputSchema.Required = properties, required
+	return tool, nil
+}
+
+// registerElementsTool 注册五行分布工具：排盘后统计原局五行个数、按月令调整后的占比及缺失、偏旺、偏弱的五行
+func registerElementsTool(mcpServer *server.Server, baziAppService *application.BaziAppService) {
+	tool, err := protocol.NewTool(ElementsToolName, "根据生辰八字统计原局五行分布：天干、按本气中气余气加权的藏干、按月令旺衰调整后的占比，以及缺失、偏旺、偏弱的五行", baziDomain.Request{})
+	if err != nil {
+		log.Fatalf("创建工具失败: %v", err)
+	}
+
+	mcpServer.RegisterTool(tool, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		var baziReq baziDomain.Request
+		if err := protocol.VerifyAndUnmarshal(req.RawArguments, &baziReq); err != nil {
+			return protocol.NewCallToolResult([]protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf("参数格式错误: %v\n请检查您的输入是否符合工具要求。", err),
+				},
+			}, true), nil
+		}
+
+		result, appErr := baziAppService.Elements(ctx, baziReq)
+		if appErr != nil {
+			log.Printf("处理五行分布请求时发生内部错误: %v", appErr)
+			return protocol.NewCallToolResult([]protocol.Content{
+				&protocol.TextContent{Type: "text", Text: providerErrorText(appErr)},
+			}, true), nil
+		}
+		return analysisResult(baziReq.Output, result.Text, result.IsError, result.JSON), nil
+	})
+}
+
+// analysisResult 按 output 返回分析文本、JSON 或两者，业务错误只返回文本说明
+func analysisResult(output, text string, isError bool, encode func() (string, error)) *protocol.CallToolResult {
+	var contents []protocol.Content
+	if isError || output != baziDomain.OutputJSON {
+		contents = append(contents, &protocol.TextContent{Type: "text", Text: text})
+	}
+	if !isError && (output == baziDomain.OutputJSON || output == baziDomain.OutputBoth) {
+		encoded, err := encode()
+		if err != nil {
+			log.Printf("编码分析结果失败: %v", err)
+			return protocol.NewCallToolResult([]protocol.Content{
+				&protocol.TextContent{Type: "text", Text: "处理请求时发生内部错误，请稍后再试或联系管理员。"},
+			}, true)
+		}
+		contents = append(contents, &protocol.TextContent{Type: "text", Text: encoded})
+	}
+	return protocol.NewCallToolResult(contents, isError)
+}
+
+// registerCompareTool 注册跨提供方排盘比对工具：同一请求分别在两个提供方排盘，返回差异摘要与 JSON
+func registerCompareTool(mcpServer *server.Server, baziAppService *application.BaziAppService) {
+	left, right := baziAppService.CompareProviders[0].Name, baziAppService.CompareProviders[1].Name
+	tool, err := protocol.NewTool(CompareToolName, fmt.Sprintf("以同一出生信息分别调用 %s 与 %s 排盘，比较四柱、十神、大运起运年份与神煞的差异", left, right), baziDomain.Request{})
+	if err != nil {
+		log.Fatalf("创建工具失败: %v", err)
+	}
+
+	mcpServer.RegisterTool(tool, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		var baziReq baziDomain.Request
+		if err := protocol.VerifyAndUnmarshal(req.RawArguments, &baziReq); err != nil {
+			return protocol.NewCallToolResult([]protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf("参数格式错误: %v\n请检查您的输入是否符合工具要求。", err),
+				},
+			}, true), nil
+		}
+
+		comparison, errMsg, isError := baziAppService.Compare(ctx, baziReq)
+		if isError {
+			return protocol.NewCallToolResult([]protocol.Content{
+				&protocol.TextContent{Type: "text", Text: errMsg},
+			}, true), nil
+		}
+		comparisonJSON, err := comparison.JSON()
+		if err != nil {
+			log.Printf("编码比对结果失败: %v", err)
+			return protocol.NewCallToolResult([]protocol.Content{
+				&protocol.TextContent{Type: "text", Text: "处理请求时发生内部错误，请稍后再试或联系管理员。"},
+			}, true), nil
+		}
+		return protocol.NewCallToolResult([]protocol.Content{
+			&protocol.TextContent{Type: "text", Text: comparison.Text()},
+			&protocol.TextContent{Type: "text", Text: comparisonJSON},
+		}, false), nil
+	})
+}
+
+// registerStatusTool 注册服务器运行状态工具：探测各排盘提供方，返回可用性、缓存统计与版本信息
+func registerStatusTool(mcpServer *server.Server, statusAppService *application.StatusAppService) {
+	tool, err := protocol.NewTool(StatusToolName, "查看服务器运行状态：各排盘提供方是否可用、排盘缓存统计与版本信息", struct{}{})
+	if err != nil {
+		log.Fatalf("创建工具失败: %v", err)
+	}
+
+	// 工具没有参数，不校验 RawArguments
+	mcpServer.RegisterTool(tool, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		status := statusAppService.Status(ctx)
+		statusJSON, err := status.JSON()
+		if err != nil {
+			log.Printf("编码运行状态失败: %v", err)
+			return protocol.NewCallToolResult([]protocol.Content{
+				&protocol.TextContent{Type: "text", Text: "处理请求时发生内部错误，请稍后再试或联系管理员。"},
+			}, true), nil
+		}
+		return protocol.NewCallToolResult([]protocol.Content{
+			&protocol.TextContent{Type: "text", Text: status.Text()},
+			&protocol.TextContent{Type: "text", Text: statusJSON},
+		}, false), nil
+	})
+}
+
+// providerErrorText 按排盘提供方的失败类别生成提示文本，其他错误不向用户暴露细节
+func providerErrorText(err error) string {
+	var providerErr *baziDomain.ProviderError
+	if !errors.As(err, &providerErr) {
+		return "处理请求时发生内部错误，请稍后再试或联系管理员。"
+	}
+	switch providerErr.Kind {
+	case baziDomain.ErrorCircuitOpen:
+		return "排盘服务连续请求失败，已暂停调用，请稍后再试。"
+	case baziDomain.ErrorNetwork:
+		return "排盘服务连接失败或超时，请稍后再试。"
+	case baziDomain.ErrorHTTPStatus:
+		return fmt.Sprintf("排盘服务暂时不可用（HTTP %d），请稍后再试。", providerErr.StatusCode)
+	case baziDomain.ErrorDecode:
+		return "排盘服务返回了无法识别的结果，请稍后再试或联系管理员。"
+	}
+	return "处理请求时发生内部错误，请稍后再试或联系管理员。"
+}
+
+// registerChartSchemaResource 注册结构化排盘结果的 JSON Schema 资源
+func registerChartSchemaResource(mcpServer *server.Server) {
+	mcpServer.RegisterResource(&protocol.Resource{
+		Name:        "chart-schema",
+		URI:         application.ChartSchemaURI,
+		Description: "bazi_paipan 在 output 为 json 或 both 时返回的结构化排盘结果的 JSON Schema",
+		MimeType:    "application/schema+json",
+	}, func(ctx context.Context, req *protocol.ReadResourceRequest) (*protocol.ReadResourceResult, error) {
+		text, err := application.ChartSchemaJSON()
+		if err != nil {
+			return nil, err
+		}
+		return &protocol.ReadResourceResult{
+			Contents: []protocol.ResourceContents{
+				protocol.TextResourceContents{URI: req.URI, MimeType: "application/schema+json", Text: text},
+			},
+		}, nil
+	})
+}
+
+// registerLocationResources 注册省份列表资源与城市列表资源模板。
+// 模板仅能匹配经百分号编码的省份名称，因此另为每个省份注册一个未编码 URI 的资源。
+func registerLocationResources(mcpServer *server.Server, locationAppService *application.LocationAppService) error {
+	mcpServer.RegisterResource(&protocol.Resource{
+		Name:        "provinces",
+		URI:         ProvincesResourceURI,
+		Description: "可用作 bazi_paipan 的 province 参数的省级行政区列表（JSON），含行政区划代码、省会及城市列表资源 URI",
+		MimeType:    "application/json",
+	}, func(ctx context.Context, req *protocol.ReadResourceRequest) (*protocol.ReadResourceResult, error) {
+		text, err := locationAppService.ProvincesJSON()
+		if err != nil {
+			return nil, err
+		}
+		return jsonResourceResult(req.URI, text), nil
+	})
+
+	citiesHandler := func(ctx context.Context, req *protocol.ReadResourceRequest) (*protocol.ReadResourceResult, error) {
+		province, ok := req.Arguments["province"].(string)
+		if !ok {
+			province, _ = url.PathUnescape(strings.TrimPrefix(req.URI, application.CitiesURIPrefix))
+		}
+		text, err := locationAppService.CitiesJSON(province)
+		if err != nil {
+			return nil, err
+		}
+		return jsonResourceResult(req.URI, text), nil
+	}
+
+	err := mcpServer.RegisterResourceTemplate(&protocol.ResourceTemplate{
+		Name:        "cities",
+		URITemplate: CitiesResourceTemplate,
+		Description: "指定省级行政区下可用作 bazi_paipan 的 city 参数的地点列表（JSON），含经纬度及可识别的别名",
+		MimeType:    "application/json",
+	}, citiesHandler)
+	if err != nil {
+		return fmt.Errorf("注册城市列表资源模板失败: %w", err)
+	}
+	for _, province := range locationAppService.Provinces().Provinces {
+		mcpServer.RegisterResource(&protocol.Resource{
+			Name:        "cities:" + province.Name,
+			URI:         province.CitiesURI,
+			Description: province.Name + "下辖地点列表（JSON）",
+			MimeType:    "application/json",
+		}, citiesHandler)
+	}
+	return nil
+}
+
+// jsonResourceResult 将 JSON 文本包装为资源读取结果
+func jsonResourceResult(uri, text string) *protocol.ReadResourceResult {
+	return &protocol.ReadResourceResult{
+		Contents: []protocol.ResourceContents{
+			protocol.TextResourceContents{URI: uri, MimeType: "application/json", Text: text},
+		},
+	}
+}
+
+// registerCalendarTool 注册公历农历互转工具及其处理程序
+func registerCalendarTool(mcpServer *server.Server, calendarAppService *application.CalendarAppService) {
+	tool, err := protocol.NewTool(CalendarToolName, "公历与农历日期互相转换，支持闰月", calendarDomain.ConvertRequest{})
+	if err != nil {
+		log.Fatalf("创建工具失败: %v", err)
+	}
+
+	mcpServer.RegisterTool(tool, func(ctx context.Context, req *protocol.CallToolRequest) (*protocol.CallToolResult, error) {
+		var convertReq calendarDomain.ConvertRequest
+		if err := protocol.VerifyAndUnmarshal(req.RawArguments, &convertReq); err != nil {
+			return protocol.NewCallToolResult([]protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: fmt.Sprintf("参数格式错误: %v\n请检查您的输入是否符合工具要求。", err),
+				},
+			}, true), nil
+		}
+
+		resultText, isAppError, appErr := calendarAppService.Convert(convertReq)
+		if appErr != nil {
+			log.Printf("处理历法转换请求时发生内部错误: %v", appErr)
+			return protocol.NewCallToolResult([]protocol.Content{
+				&protocol.TextContent{
+					Type: "text",
+					Text: "处理请求时发生内部错误，请稍后再试或联系管理员。",
+				},
+			}, true), nil
+		}
+
 		return protocol.NewCallToolResult([]protocol.Content{
 			&protocol.TextContent{
 				Type: "text",
-				Text: resultText, // 应用服务已格式化好文本
+				Text: resultText,
 			},
-		}, isAppError), nil // 根据应用服务的结果设置 IsError 标志
+		}, isAppError), nil
 	})
 }
 
 // 3. 迁移提示词内容到领域层（保持领域知识内聚，明确MCP协议层职责）
-func registerPrompts(mcpServer *server.Server) {
-	// 创建八字排盘提示词
-	baziPrompt := &protocol.Prompt{
-		Name:        "bazi_prompt",
-		Description: baziDomain.PromptDescription,    // 引用领域层定义的常量
-		Arguments:   baziDomain.GetPromptArguments(), // 使用领域层构造方法
-	}
+func registerPrompts(mcpServer *server.Server, baziAppService *application.BaziAppService) {
+	for _, template := range baziDomain.PromptTemplates {
+		prompt := &protocol.Prompt{
+			Name:        template.Name,
+			Description: template.Description,
+			Arguments:   template.Arguments(), // 使用领域层构造方法
+		}
 
-	mcpServer.RegisterPrompt(baziPrompt, func(ctx context.Context, request *protocol.GetPromptRequest) (*protocol.GetPromptResult, error) {
-		return baziDomain.GeneratePromptContent() // 将具体实现移交领域层
-	})
+		mcpServer.RegisterPrompt(prompt, func(ctx context.Context, request *protocol.GetPromptRequest) (*protocol.GetPromptResult, error) {
+			return baziAppService.GetPrompt(ctx, request.Name, request.Arguments) // 排盘后由领域层生成提示内容
+		})
+	}
 }
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	application "github.com/justinwongcn/bazi-mcp/internal/application"
+	baziCache "github.com/justinwongcn/bazi-mcp/internal/infrastructure/bazi/cache"
+)
+
+// version 版本号，构建时可通过 -ldflags "-X main.version=v1.2.0" 指定
+var version = "dev"
+
+// healthCheckTimeout 启动时探测排盘提供方的超时
+const healthCheckTimeout = 10 * time.Second
+
+// versionInfo 返回版本号、构建时的代码版本与 Go 版本
+func versionInfo() application.VersionInfo {
+	info := application.VersionInfo{Version: version, GoVersion: runtime.Version()}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "dev" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+			info.Version = build.Main.Version
+		}
+		for _, setting := range build.Settings {
+			if setting.Key == "vcs.revision" && len(setting.Value) >= 12 {
+				info.Revision = setting.Value[:12]
+			}
+		}
+	}
+	return info
+}
+
+// cacheStatus 将缓存统计转换为应用层的缓存状态
+func cacheStatus(cache *baziCache.Service) func() application.CacheStatus {
+	return func() application.CacheStatus {
+		stats := cache.Stats()
+		status := application.CacheStatus{
+			Entries:  stats.Entries,
+			Capacity: stats.Capacity,
+			Hits:     stats.Hits + stats.DiskHits,
+			DiskHits: stats.DiskHits,
+			Misses:   stats.Misses,
+		}
+		if stats.Disk {
+			status.DiskEntries = &stats.DiskEntries
+		}
+		return status
+	}
+}
+
+// checkProviders 启动前探测各排盘提供方并记录结果，全部不可用时返回错误
+func checkProviders(statusAppService *application.StatusAppService) error {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	reachable := false
+	for _, p := range statusAppService.Status(ctx).Providers {
+		if p.Reachable {
+			reachable = true
+			log.Printf("排盘提供方 %s 可用，耗时 %dms，%s\n", p.Name, p.LatencyMS, p.Detail)
+		} else {
+			log.Printf("排盘提供方 %s 不可用：%s\n", p.Name, p.Detail)
+		}
+	}
+	if !reachable {
+		return fmt.Errorf("健康检查失败：没有可用的排盘提供方")
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/justinwongcn/bazi-mcp/internal/infrastructure/config"
+
+	mcpTransport "github.com/ThinkInAIXYZ/go-mcp/transport"
+)
+
+// transport 默认传输方式，构建时可通过 -ldflags "-X main.transport=stdio" 指定；
+// 运行时依次被配置文件、环境变量 BAZI_TRANSPORT 与命令行参数 -transport 覆盖
+var transport = "stdio"
+
+// 各传输方式在基础路径下的端点
+const (
+	sseEndpoint     = "/sse"
+	messageEndpoint = "/message"
+	mcpEndpoint     = "/mcp"
+)
+
+// newServerTransport 按配置创建 MCP 服务端传输
+func newServerTransport(cfg config.Transport) (mcpTransport.ServerTransport, error) {
+	switch cfg.Mode {
+	case config.TransportSSE:
+		return mcpTransport.NewSSEServerTransport(cfg.Addr,
+			mcpTransport.WithSSEServerTransportOptionSSEPath(cfg.BasePath+sseEndpoint),
+			mcpTransport.WithSSEServerTransportOptionMessagePath(cfg.BasePath+messageEndpoint),
+		)
+	case config.TransportHTTP:
+		return mcpTransport.NewStreamableHTTPServerTransport(cfg.Addr,
+			mcpTransport.WithStreamableHTTPServerTransportOptionEndpoint(cfg.BasePath+mcpEndpoint),
+		), nil
+	default:
+		return mcpTransport.NewStdioServerTransport(), nil
+	}
+}
+
+// describeTransport 返回传输方式及其端点的说明
+func describeTransport(cfg config.Transport) string {
+	switch cfg.Mode {
+	case config.TransportSSE:
+		return fmt.Sprintf("SSE 模式，监听 %s，SSE 端点 %s，消息端点 %s", cfg.Addr, cfg.BasePath+sseEndpoint, cfg.BasePath+messageEndpoint)
+	case config.TransportHTTP:
+		return fmt.Sprintf("Streamable HTTP 模式，监听 %s，端点 %s", cfg.Addr, cfg.BasePath+mcpEndpoint)
+	default:
+		return "stdio 模式"
+	}
+}
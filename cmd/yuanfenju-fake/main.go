@@ -0,0 +1,29 @@
+// yuanfenju-fake 在本地运行模拟的缘分居排盘接口，排盘结果由本地排盘引擎计算，
+// 配合 BAZI_API_ENDPOINT 可在离线环境中调试 bazi-mcp 的接口调用、重试与熔断。
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/infrastructure/bazi/fake"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "监听地址")
+	var behavior fake.Behavior
+	flag.IntVar(&behavior.Status, "status", 0, "返回的 HTTP 状态码，例：503")
+	flag.IntVar(&behavior.ErrCode, "errcode", 0, "返回的业务错误码")
+	flag.StringVar(&behavior.ErrMsg, "errmsg", "模拟的业务错误", "业务错误说明")
+	flag.DurationVar(&behavior.Latency, "latency", 0, "响应延迟，例：2s")
+	flag.BoolVar(&behavior.Malformed, "malformed", false, "返回无法解析的响应体")
+	flag.Parse()
+
+	handler := fake.NewHandler()
+	handler.SetBehavior(behavior)
+	log.Printf("模拟排盘接口监听 %s，BAZI_API_ENDPOINT=http://localhost%s/v1/Bazi", *addr, *addr)
+	server := &http.Server{Addr: *addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	log.Fatal(server.ListenAndServe())
+}
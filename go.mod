@@ -4,17 +4,18 @@ go 1.24.2
 
 require (
 	github.com/ThinkInAIXYZ/go-mcp v0.2.2
+	github.com/adrg/strutil v0.3.1
 	github.com/lithammer/fuzzysearch v1.1.8
+	github.com/mozillazg/go-pinyin v0.20.0
+	github.com/yosida95/uritemplate/v3 v3.0.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/adrg/strutil v0.3.1 // indirect
 	github.com/google/uuid v1.6.0 // indirect
-	github.com/mozillazg/go-pinyin v0.20.0 // indirect
 	github.com/orcaman/concurrent-map/v2 v2.0.1 // indirect
 	github.com/tidwall/gjson v1.18.0 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.0 // indirect
-	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
 	golang.org/x/text v0.9.0 // indirect
 )
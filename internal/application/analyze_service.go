@@ -0,0 +1,271 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// Analysis 命局分析，由排盘结果中的四柱与藏干在本地计算
+type Analysis struct {
+	Name            string              `json:"name"`              // 姓名
+	Bazi            []string            `json:"bazi"`              // 四柱干支（按年月日时排序）
+	Strength        bazi.Strength       `json:"strength"`          // 日主强弱
+	Elements        bazi.ElementBalance `json:"elements"`          // 五行分布
+	UsefulGod       bazi.UsefulGod      `json:"useful_god"`        // 用神喜忌及推导步骤
+	Pattern         bazi.PatternResult  `json:"pattern"`           // 本地判定的格局及依据
+	ProviderPattern string              `json:"provider_pattern"`  // 提供方给出的八字正格，可与 Pattern.Regular 对照
+	Relations       []bazi.Relation     `json:"relations"`         // 原局干支的合冲刑害破
+	Transit         *TransitAnalysis    `json:"transit,omitempty"` // 当年大运、流年与原局的合冲刑害破
+}
+
+// TransitAnalysis 某一年所行大运、流年与原局干支的关系
+type TransitAnalysis struct {
+	Year      int             `json:"year"`            // 公历年份
+	Dayun     string          `json:"dayun,omitempty"` // 所行大运干支，尚未起运或大运信息无效时为空
+	LiuNian   string          `json:"liunian"`         // 流年干支
+	Relations []bazi.Relation `json:"relations"`       // 大运、流年与原局干支的合冲刑害破
+}
+
+// AnalysisResult 命局分析的文本与结构化两种形式
+type AnalysisResult struct {
+	Text     string    // 分析文本，出错时为错误说明
+	Analysis *Analysis // 结构化分析结果，仅分析成功时非空
+	IsError  bool      // 是否为业务错误
+}
+
+// NewAnalysis 分析排盘结果，schools 为取用神的流派，year 为分析大运流年关系的公历年份，不大于 0 时不分析。
+// 八字信息无效时返回 bazi.ErrInvalidBazi
+func NewAnalysis(data bazi.Data, schools []bazi.School, year int) (Analysis, error) {
+	strength, err := bazi.AnalyzeStrength(data.BaziInfo)
+	if err != nil {
+		return Analysis{}, err
+	}
+	elements, err := bazi.AnalyzeElements(data.BaziInfo)
+	if err != nil {
+		return Analysis{}, err
+	}
+	usefulGod, err := bazi.DetermineUsefulGod(data.BaziInfo, schools...)
+	if err != nil {
+		return Analysis{}, err
+	}
+	pattern, err := bazi.ClassifyPattern(data.BaziInfo)
+	if err != nil {
+		return Analysis{}, err
+	}
+	relations, err := bazi.NatalRelations(data.BaziInfo)
+	if err != nil {
+		return Analysis{}, err
+	}
+	analysis := Analysis{
+		Name:            data.BaseInfo.Name,
+		Bazi:            data.BaziInfo.Bazi,
+		Strength:        strength,
+		Elements:        elements,
+		UsefulGod:       usefulGod,
+		Pattern:         pattern,
+		ProviderPattern: data.BaseInfo.Zhengge,
+		Relations:       relations,
+	}
+	if year > 0 {
+		transit, err := newTransitAnalysis(data, year)
+		if err != nil {
+			return Analysis{}, err
+		}
+		analysis.Transit = &transit
+	}
+	return analysis, nil
+}
+
+// newTransitAnalysis 找出 year 年所行大运与流年干支同原局的关系，大运信息无效时只分析流年
+func newTransitAnalysis(data bazi.Data, year int) (TransitAnalysis, error) {
+	t := TransitAnalysis{Year: year, LiuNian: bazi.YearGanZhi(year)}
+	transits := []bazi.Transit{{Pillar: bazi.LiuNianPillar, Ganzhi: t.LiuNian}}
+	if periods, err := bazi.NewDayunPeriods(data.DayunInfo, data.DetailInfo.Dayunshensha); err == nil {
+		for _, p := range periods {
+			if p.StartYear <= year && year <= p.EndYear {
+				t.Dayun = p.Ganzhi
+				transits = append([]bazi.Transit{{Pillar: bazi.DayunPillar, Ganzhi: p.Ganzhi}}, transits...)
+				break
+			}
+		}
+	}
+	relations, err := bazi.TransitRelations(data.BaziInfo, transits...)
+	if err != nil {
+		return TransitAnalysis{}, err
+	}
+	t.Relations = relations
+	return t, nil
+}
+
+// ElementsResult 五行分布的文本与结构化两种形式
+type ElementsResult struct {
+	Text     string               // 五行分布文本，出错时为错误说明
+	Elements *bazi.ElementBalance // 结构化五行分布，仅分析成功时非空
+	IsError  bool                 // 是否为业务错误
+}
+
+// Elements 排盘后统计原局五行分布，输入有误或提供方返回业务错误时返回错误说明文本
+func (s *BaziAppService) Elements(ctx context.Context, req bazi.Request) (ElementsResult, error) {
+	result, err := s.Analyze(ctx, bazi.AnalyzeRequest{Request: req})
+	if err != nil || result.IsError {
+		return ElementsResult{Text: result.Text, IsError: true}, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "姓名：%s\n八字：%s\n", result.Analysis.Name, strings.Join(result.Analysis.Bazi, " "))
+	writeElements(&b, result.Analysis.Elements)
+	return ElementsResult{Text: b.String(), Elements: &result.Analysis.Elements}, nil
+}
+
+// JSON 返回五行分布的 JSON 文本
+func (r ElementsResult) JSON() (string, error) {
+	if r.Elements == nil {
+		return "", fmt.Errorf("无五行分布结果")
+	}
+	return marshalResource(r.Elements)
+}
+
+// Analyze 排盘后分析命局，输入有误或提供方返回业务错误时返回错误说明文本。
+// 大运流年按 req.TargetYear 取，未填写时取当前年份。
+func (s *BaziAppService) Analyze(ctx context.Context, req bazi.AnalyzeRequest) (AnalysisResult, error) {
+	if req.TargetYear < 0 {
+		return AnalysisResult{Text: fmt.Sprintf("无效流年年份: %d\n 例：2025，未填写时取当前年份", req.TargetYear), IsError: true}, nil
+	}
+	resp, _, errMsg, err := s.fetchPaipan(ctx, &req.Request)
+	if errMsg != "" {
+		return AnalysisResult{Text: errMsg, IsError: true}, nil
+	}
+	if err != nil {
+		return AnalysisResult{IsError: true}, err
+	}
+	if resp.ErrCode != 0 {
+		text, _, err := s.handleAPIResponse(req.Request, resp)
+		return AnalysisResult{Text: text, IsError: true}, err
+	}
+
+	year := req.TargetYear
+	if year == 0 {
+		year = time.Now().In(beijing).Year()
+	}
+	analysis, err := NewAnalysis(resp.Data, s.Schools, year)
+	if err != nil {
+		return AnalysisResult{IsError: true}, fmt.Errorf("分析排盘结果失败: %w", err)
+	}
+	return AnalysisResult{Text: analysis.Text(), Analysis: &analysis}, nil
+}
+
+// Text 返回命局分析的文本
+func (a Analysis) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "【命局分析】\n姓名：%s\n八字：%s\n", a.Name, strings.Join(a.Bazi, " "))
+	writeStrength(&b, a.Strength)
+	writeElements(&b, a.Elements)
+	writePattern(&b, a.Pattern, a.ProviderPattern)
+	writeUsefulGod(&b, a.UsefulGod)
+	writeRelations(&b, a.Relations, a.Transit)
+	return b.String()
+}
+
+// JSON 返回命局分析的 JSON 文本
+func (a Analysis) JSON() (string, error) {
+	return marshalResource(a)
+}
+
+// writeStrength 输出日主强弱及得令、得地、得势的得分明细
+func writeStrength(b *strings.Builder, s bazi.Strength) {
+	b.WriteString("\n【日主强弱】\n")
+	fmt.Fprintf(b, "日主：%s%s，月令旺衰：%s\n", s.DayMaster, s.Element, s.Season)
+	fmt.Fprintf(b, "得分：%s / 100（%s）\n", bazi.FormatFloat(s.Score), s.Level)
+	for _, f := range s.Factors {
+		var items []string
+		for _, item := range f.Items {
+			if item.Score > 0 {
+				items = append(items, fmt.Sprintf("%s %s（%s）+%s", item.Position, item.Stem, item.TenGod, bazi.FormatFloat(item.Score)))
+			}
+		}
+		if len(items) == 0 {
+			items = append(items, "无生扶")
+		}
+		fmt.Fprintf(b, "  %s %s / %s：%s\n", f.Name, bazi.FormatFloat(f.Score), bazi.FormatFloat(f.Max), strings.Join(items, "，"))
+	}
+}
+
+// writeElements 输出五行分布及缺失、偏旺、偏弱的五行
+func writeElements(b *strings.Builder, e bazi.ElementBalance) {
+	b.WriteString("\n【五行分布】\n")
+	for _, c := range e.Elements {
+		fmt.Fprintf(b, "%s：天干 %d，藏干 %s，合计 %s；月令%s，调整后 %s（%s%%）\n",
+			c.Element, c.Stems, bazi.FormatFloat(c.Hidden), bazi.FormatFloat(c.Total), c.Season, bazi.FormatFloat(c.Adjusted), bazi.FormatFloat(c.Share))
+	}
+	fmt.Fprintf(b, "缺失：%s\n偏旺：%s\n偏弱：%s\n", joinOrNone(e.Missing), joinOrNone(e.Excess), joinOrNone(e.Weak))
+}
+
+// writePattern 输出判定的格局、规则、依据与可信度，并与提供方的八字正格对照
+func writePattern(b *strings.Builder, r bazi.PatternResult, provider string) {
+	p := r.Pattern
+	b.WriteString("\n【格局】\n")
+	fmt.Fprintf(b, "格局：%s（%s，可信度%s）\n规则：%s\n依据：%s\n", p.Name, p.Category, p.Confidence, p.Rule, strings.Join(p.Evidence, "；"))
+	fmt.Fprintf(b, "月令正格：%s（%s，可信度%s）\n", r.Regular.Name, r.Regular.Rule, r.Regular.Confidence)
+	switch provider {
+	case "":
+		b.WriteString("提供方正格：未提供\n")
+	case r.Regular.Name:
+		fmt.Fprintf(b, "提供方正格：%s，与月令正格一致\n", provider)
+	default:
+		fmt.Fprintf(b, "提供方正格：%s，与月令正格不同\n", provider)
+	}
+	b.WriteString("特殊格局检查：\n")
+	for _, c := range r.Checks {
+		verdict := "不成立"
+		if c.Matched {
+			verdict = "成立"
+		}
+		fmt.Fprintf(b, "  %s：%s，%s\n", c.Category, verdict, c.Finding)
+	}
+}
+
+// writeUsefulGod 输出用神喜忌及逐步的推导依据
+func writeUsefulGod(b *strings.Builder, u bazi.UsefulGod) {
+	b.WriteString("\n【用神喜忌】\n")
+	fmt.Fprintf(b, "取用流派：%s\n用神：%s\n喜神：%s\n忌神：%s\n仇神：%s\n推导：\n", u.School, u.Useful, u.Favorable, u.Unfavorable, u.Hostile)
+	for i, step := range u.Steps {
+		rule := step.Rule
+		if step.School != "" {
+			rule = string(step.School) + "·" + rule
+		}
+		fmt.Fprintf(b, "  %d. [%s] %s → %s\n", i+1, rule, step.Finding, step.Conclusion)
+	}
+}
+
+// writeRelations 输出原局干支的合冲刑害破，transit 非空时附上当年大运、流年与原局的关系
+func writeRelations(b *strings.Builder, relations []bazi.Relation, transit *TransitAnalysis) {
+	b.WriteString("\n【刑冲合害】\n原局：\n")
+	writeRelationList(b, relations)
+	if transit == nil {
+		return
+	}
+	dayun := "未起运"
+	if transit.Dayun != "" {
+		dayun = "大运" + transit.Dayun
+	}
+	fmt.Fprintf(b, "%d年（%s，流年%s）：\n", transit.Year, dayun, transit.LiuNian)
+	writeRelationList(b, transit.Relations)
+}
+
+// writeRelationList 逐条输出干支关系及参与的柱位，无关系时输出“无”
+func writeRelationList(b *strings.Builder, relations []bazi.Relation) {
+	if len(relations) == 0 {
+		b.WriteString("  无\n")
+	}
+	for _, r := range relations {
+		fmt.Fprintf(b, "  %s：%s（%s）\n", r.Kind, r.Name, r.Positions())
+	}
+}
+
+// joinOrNone 以顿号连接，空列表显示为“无”
+func joinOrNone(items []string) string {
+	return orNone(strings.Join(items, "、"))
+}
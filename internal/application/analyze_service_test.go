@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+func TestAnalyze(t *testing.T) {
+	service := NewBaziAppService(&stubService{resp: loadTestData(t)}, nil, nil)
+	req := bazi.AnalyzeRequest{Request: bazi.Request{Name: "张三", Sex: 0, Type: 1, Year: 2000, Month: 1, Day: 2, Hours: 3, Minute: 4}}
+
+	result, err := service.Analyze(context.Background(), req)
+	if err != nil || result.IsError || result.Analysis == nil {
+		t.Fatalf("Analyze() = %+v, %v", result, err)
+	}
+	for _, want := range []string{"八字：己卯 丙子 己未 丙寅", "日主：己土，月令旺衰：囚", "得分：42.5 / 100（偏弱）", "得势 25 / 25：年干 己（比肩）+7", "得令 0 / 40：无生扶",
+		"用神：火（印星）", "仇神：金（食伤）", "2. [扶抑·克泄耗中官杀最重]", "格局：偏财格（正格，可信度低）", "提供方正格：偏财格，与月令正格一致", "专旺格：不成立，日主土在子月囚，不当令",
+		"【刑冲合害】", "半合：卯未半合木局（年支卯、日支未）", "三刑：子卯无礼之刑（年支卯、月支子）"} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("分析文本应包含 %q:\n%s", want, result.Text)
+		}
+	}
+
+	text, err := result.Analysis.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Analysis
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil || decoded.Strength.Score != 42.5 || len(decoded.Strength.Factors) != 3 || len(decoded.UsefulGod.Steps) != 3 || len(decoded.Relations) != 3 || decoded.Transit == nil ||
+		decoded.Pattern.Regular.Name != decoded.ProviderPattern || len(decoded.Pattern.Checks) != 3 {
+		t.Errorf("JSON() = %s, %v", text, err)
+	}
+
+	// 按配置的流派取用神
+	service.Schools = []bazi.School{bazi.SchoolTongGuan, bazi.SchoolTiaoHou}
+	if result, err := service.Analyze(context.Background(), req); err != nil || result.Analysis.UsefulGod.School != bazi.SchoolTiaoHou || !strings.Contains(result.Text, "[调候·冬生寒冻]") {
+		t.Errorf("调候取用 = %+v, %v", result.Analysis, err)
+	}
+	service.Schools = nil
+
+	// 指定流年年份
+	req.TargetYear = 2024
+	if result, err := service.Analyze(context.Background(), req); err != nil || result.Analysis.Transit.Year != 2024 || !strings.Contains(result.Text, "2024年（大运甲戌，流年甲辰）") {
+		t.Errorf("指定流年 = %+v, %v", result.Analysis, err)
+	}
+	req.TargetYear = -1
+	if result, err := service.Analyze(context.Background(), req); err != nil || !result.IsError || !strings.Contains(result.Text, "无效流年年份") {
+		t.Errorf("无效流年年份 = %+v, %v", result, err)
+	}
+	req.TargetYear = 0
+
+	// 排盘文本同样包含日主强弱、五行分布、格局与原局刑冲合害，结构化排盘结果包含五行分布、干支关系与格局
+	paipan, err := service.Paipan(context.Background(), req.Request)
+	if err != nil || !strings.Contains(paipan.Text, "【日主强弱】") || !strings.Contains(paipan.Text, "【五行分布】") || !strings.Contains(paipan.Text, "六害：子未相害（月支子、日支未）") ||
+		!strings.Contains(paipan.Text, "月令正格：偏财格") {
+		t.Errorf("排盘文本应包含日主强弱、五行分布、格局与刑冲合害: %v", err)
+	}
+	if paipan.Chart == nil || paipan.Chart.Elements == nil || paipan.Chart.Elements.Elements[bazi.Tu].Total != 2.7 || len(paipan.Chart.Relations) != 3 ||
+		paipan.Chart.PatternDetail == nil || paipan.Chart.PatternDetail.Pattern.Name != paipan.Chart.Pattern {
+		t.Errorf("结构化排盘结果的五行分布、干支关系与格局 = %+v", paipan.Chart)
+	}
+
+	business := &stubService{err: &bazi.ProviderError{Kind: bazi.ErrorBusiness, Provider: "test", ErrCode: 10003, Message: "出生年份超出范围"}}
+	if result, err := NewBaziAppService(business, nil, nil).Analyze(context.Background(), req); err != nil || !result.IsError || !strings.Contains(result.Text, "出生年份超出范围") {
+		t.Errorf("业务错误应作为输入错误提示: %+v, %v", result, err)
+	}
+	req.Province = "火星"
+	if result, _ := service.Analyze(context.Background(), req); !result.IsError || result.Analysis != nil {
+		t.Errorf("无效输入应返回错误: %q", result.Text)
+	}
+}
+
+func TestNewAnalysisTransit(t *testing.T) {
+	data := loadTestData(t).Data
+	analysis, err := NewAnalysis(data, nil, 2024)
+	if err != nil || analysis.Transit == nil {
+		t.Fatalf("NewAnalysis() = %+v, %v", analysis, err)
+	}
+	transit := analysis.Transit
+	var names []string
+	for _, r := range transit.Relations {
+		names = append(names, r.Name)
+	}
+	want := []string{"甲己合土", "甲己合土", "甲己合土", "甲己合土", "卯戌合火", "寅卯辰三会木方", "子辰半合水局", "未戌恃势之刑", "卯辰相害", "未戌相破"}
+	if transit.Dayun != "甲戌" || transit.LiuNian != "甲辰" || strings.Join(names, " ") != strings.Join(want, " ") {
+		t.Errorf("2024 年大运流年关系 = %+v", transit)
+	}
+	if text := analysis.Text(); !strings.Contains(text, "2024年（大运甲戌，流年甲辰）：") || !strings.Contains(text, "地支六合：卯戌合火（年支卯、大运支戌）") {
+		t.Errorf("分析文本应包含大运流年关系:\n%s", text)
+	}
+
+	// 尚未起运时只分析流年；年份不大于 0 时不分析
+	if analysis, err := NewAnalysis(data, nil, 2005); err != nil || analysis.Transit.Dayun != "" || !strings.Contains(analysis.Text(), "2005年（未起运，流年乙酉）：") {
+		t.Errorf("未起运 = %+v, %v", analysis.Transit, err)
+	}
+	if analysis, err := NewAnalysis(data, nil, 0); err != nil || analysis.Transit != nil {
+		t.Errorf("year=0 = %+v, %v", analysis.Transit, err)
+	}
+}
+
+func TestElements(t *testing.T) {
+	service := NewBaziAppService(&stubService{resp: loadTestData(t)}, nil, nil)
+	req := bazi.Request{Name: "张三", Sex: 0, Type: 1, Year: 2000, Month: 1, Day: 2, Hours: 3, Minute: 4}
+
+	result, err := service.Elements(context.Background(), req)
+	if err != nil || result.IsError || result.Elements == nil {
+		t.Fatalf("Elements() = %+v, %v", result, err)
+	}
+	for _, want := range []string{"【五行分布】", "土：天干 2，藏干 0.7，合计 2.7；月令囚，调整后 2.16（29.8%）", "缺失：金", "偏旺：无"} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("五行分布文本应包含 %q:\n%s", want, result.Text)
+		}
+	}
+	text, err := result.JSON()
+	var decoded bazi.ElementBalance
+	if err != nil || json.Unmarshal([]byte(text), &decoded) != nil || len(decoded.Elements) != 5 || decoded.Missing[0] != "金" {
+		t.Errorf("JSON() = %s, %v", text, err)
+	}
+
+	req.Province = "火星"
+	if result, _ := service.Elements(context.Background(), req); !result.IsError || result.Elements != nil {
+		t.Errorf("无效输入应返回错误: %q", result.Text)
+	}
+}
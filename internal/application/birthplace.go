@@ -0,0 +1,176 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+	"github.com/justinwongcn/bazi-mcp/internal/domain/location"
+)
+
+// birthplace 排盘使用的出生地：坐标及出生时间所用的当地时区
+type birthplace struct {
+	label       string         // 展示名称
+	longitude   float64        // 经度，东经为正
+	latitude    float64        // 纬度，北纬为正
+	zone        *time.Location // 当地时区，国内地点为北京时间
+	approximate string         // 坐标近似说明，为空表示坐标准确
+}
+
+// validateBirthplace 校验显式填写的经纬度与时区
+func validateBirthplace(req *bazi.Request) (errMsg string, hasError bool) {
+	if (req.Longitude == nil) != (req.Latitude == nil) {
+		return "经度 longitude 与纬度 latitude 需同时填写", true
+	}
+	if req.Longitude != nil {
+		if err := location.ValidateCoordinate(*req.Longitude, *req.Latitude); err != nil {
+			return fmt.Sprintf("无效出生地坐标: %v\n 经度范围 -180~180（西经为负），纬度范围 -90~90（南纬为负）", err), true
+		}
+	}
+	if req.Timezone != "" {
+		if req.Longitude == nil {
+			return "时区 timezone 需与经度 longitude、纬度 latitude 一同填写；境外城市也可只填写 city，如 \"纽约\"", true
+		}
+		if _, err := time.LoadLocation(req.Timezone); err != nil {
+			return fmt.Sprintf("无效时区: %s\n 请使用 IANA 时区名称，例：\"America/New_York\"", req.Timezone), true
+		}
+	}
+	return "", false
+}
+
+// matchWorldCity 在境外城市地名录中查找城市，命中时将 city 改写为规范中文名并返回匹配说明
+func (s *BaziAppService) matchWorldCity(req *bazi.Request) (note string, ok bool) {
+	if s.WorldGazetteer == nil {
+		return "", false
+	}
+	city, ok := s.WorldGazetteer.Lookup(req.City)
+	if !ok {
+		return "", false
+	}
+	note = fmt.Sprintf("\n【出生地匹配】\n“%s”为境外城市“%s（%s，%s）”，出生时间按当地时区理解\n", req.City, city.Name, city.Country, city.TimeZone)
+	req.City = city.Name
+	return note, true
+}
+
+// resolveBirthplace 依次按显式经纬度、国内地名录、境外城市地名录确定出生地
+func (s *BaziAppService) resolveBirthplace(req bazi.Request) (birthplace, bool) {
+	if req.Longitude != nil && req.Latitude != nil {
+		place := birthplace{label: req.City, longitude: *req.Longitude, latitude: *req.Latitude, zone: beijing}
+		if place.label == "" {
+			place.label = "自定义坐标"
+		}
+		if req.Timezone != "" {
+			zone, err := time.LoadLocation(req.Timezone)
+			if err != nil {
+				return birthplace{}, false
+			}
+			place.zone = zone
+		}
+		return place, true
+	}
+
+	if req.City == "" {
+		return birthplace{}, false
+	}
+	if req.Province != "" {
+		if s.Gazetteer == nil {
+			return birthplace{}, false
+		}
+		p, ok := s.Gazetteer.Lookup(req.Province, req.City)
+		if !ok {
+			return birthplace{}, false
+		}
+		place := birthplace{label: p.Province + " " + p.City, longitude: p.Longitude, latitude: p.Latitude, zone: beijing}
+		if p.Approximate {
+			seat, _ := s.Gazetteer.Seat(p.Province)
+			place.approximate = fmt.Sprintf("未收录该地坐标，按省会%s近似", seat.City)
+		}
+		return place, true
+	}
+
+	if s.WorldGazetteer == nil {
+		return birthplace{}, false
+	}
+	city, ok := s.WorldGazetteer.Lookup(req.City)
+	if !ok {
+		return birthplace{}, false
+	}
+	zone, err := city.Location()
+	if err != nil {
+		return birthplace{}, false
+	}
+	return birthplace{label: city.Country + " " + city.Name, longitude: city.Longitude, latitude: city.Latitude, zone: zone}, true
+}
+
+// localCivilTime 返回出生时间在当地时区的钟表时刻；落在夏令时切换跳过的区间时返回提示
+func localCivilTime(req bazi.Request, zone *time.Location) (time.Time, string) {
+	civil := time.Date(req.Year, time.Month(req.Month), req.Day, req.Hours, req.Minute, 0, 0, zone)
+	if civil.Hour() != req.Hours || civil.Minute() != req.Minute {
+		return civil, fmt.Sprintf("⚠️ 当地时间 %d时%d分 因夏令时切换并不存在，已按 %s 处理\n", req.Hours, req.Minute, formatClock(civil))
+	}
+	return civil, ""
+}
+
+// convertTimeZone 出生地不在北京时间时区且未校正真太阳时时，将当地钟表时间换算为北京时间
+func (s *BaziAppService) convertTimeZone(req *bazi.Request) string {
+	place, ok := s.resolveBirthplace(*req)
+	if !ok || place.zone == beijing {
+		return ""
+	}
+
+	civil, warning := localCivilTime(*req, place.zone)
+	chart := civil.In(beijing)
+	req.Year, req.Month, req.Day = chart.Year(), int(chart.Month()), chart.Day()
+	req.Hours, req.Minute = chart.Hour(), chart.Minute()
+
+	var builder strings.Builder
+	builder.WriteString("\n【时区换算】\n")
+	fmt.Fprintf(&builder, "出生地：%s（%s）\n", place.label, place.zone)
+	fmt.Fprintf(&builder, "当地时间：%s（%s）\n", formatClock(civil), formatUTCOffset(civil))
+	builder.WriteString(warning)
+	fmt.Fprintf(&builder, "北京时间：%s（排盘以北京时间为准）\n", formatClock(chart))
+	builder.WriteString("提示：时柱按北京时间起算，境外出生建议设置 zhen=1，按出生地真太阳时排盘\n")
+	return builder.String()
+}
+
+// formatCoordinate 将经纬度格式化为“东经x° 北纬y°”
+func formatCoordinate(longitude, latitude float64) string {
+	return formatLongitude(longitude) + " " + formatLatitude(latitude)
+}
+
+// formatLongitude 将经度格式化为“东经x°”或“西经x°”
+func formatLongitude(longitude float64) string {
+	if longitude < 0 {
+		return "西经" + strconv.FormatFloat(-longitude, 'f', -1, 64) + "°"
+	}
+	return "东经" + strconv.FormatFloat(longitude, 'f', -1, 64) + "°"
+}
+
+// formatLatitude 将纬度格式化为“北纬x°”或“南纬x°”
+func formatLatitude(latitude float64) string {
+	if latitude < 0 {
+		return "南纬" + strconv.FormatFloat(-latitude, 'f', -1, 64) + "°"
+	}
+	return "北纬" + strconv.FormatFloat(latitude, 'f', -1, 64) + "°"
+}
+
+// formatUTCOffset 将时刻所在时区的偏移格式化为“UTC+8”“UTC-4”“UTC+5:30”
+func formatUTCOffset(t time.Time) string {
+	_, offset := t.Zone()
+	return formatOffset(time.Duration(offset) * time.Second)
+}
+
+// formatOffset 将相对 UTC 的偏移格式化为“UTC+8”“UTC-4”“UTC+5:30”
+func formatOffset(offset time.Duration) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	hours, minutes := int(offset/time.Hour), int(offset%time.Hour/time.Minute)
+	if minutes != 0 {
+		return fmt.Sprintf("UTC%s%d:%02d", sign, hours, minutes)
+	}
+	return fmt.Sprintf("UTC%s%d", sign, hours)
+}
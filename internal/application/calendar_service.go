@@ -0,0 +1,77 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+	"github.com/justinwongcn/bazi-mcp/internal/domain/calendar"
+)
+
+var weekdays = []string{"日", "一", "二", "三", "四", "五", "六"}
+
+// CalendarAppService 定义了公历与农历互相转换的应用服务。
+type CalendarAppService struct{}
+
+// NewCalendarAppService 创建一个新的 CalendarAppService 实例。
+func NewCalendarAppService() *CalendarAppService {
+	return &CalendarAppService{}
+}
+
+// Convert 按请求方向转换日期，返回格式化文本及是否为业务错误。
+func (s *CalendarAppService) Convert(req calendar.ConvertRequest) (string, bool, error) {
+	var (
+		solar time.Time
+		lunar calendar.LunarDate
+		err   error
+	)
+	switch req.Type {
+	case calendar.LunarToSolarType:
+		lunar = calendar.LunarDate{Year: req.Year, Month: req.Month, Day: req.Day, Leap: req.Leap}
+		solar, err = calendar.LunarToSolar(req.Year, req.Month, req.Day, req.Leap)
+	case calendar.SolarToLunarType:
+		solar = time.Date(req.Year, time.Month(req.Month), req.Day, 0, 0, 0, 0, time.UTC)
+		lunar, err = calendar.SolarToLunar(req.Year, req.Month, req.Day)
+	default:
+		return fmt.Sprintf("❌ 无效的转换方向: %d，仅支持 0（农历转公历）或 1（公历转农历）", req.Type), true, nil
+	}
+	if err != nil {
+		if errors.Is(err, calendar.ErrInvalidDate) {
+			return "❌ 历法转换失败：" + err.Error(), true, nil
+		}
+		return "", true, fmt.Errorf("历法转换失败: %w", err)
+	}
+
+	return s.formatConversion(solar, lunar)
+}
+
+// formatConversion 生成转换结果文本，附带农历年干支、闰月及当月天数
+func (s *CalendarAppService) formatConversion(solar time.Time, lunar calendar.LunarDate) (string, bool, error) {
+	days, err := calendar.MonthDays(lunar.Year, lunar.Month, lunar.Leap)
+	if err != nil {
+		return "", true, fmt.Errorf("获取农历月天数失败: %w", err)
+	}
+	yearIndex := ((lunar.Year-4)%60 + 60) % 60
+
+	var builder strings.Builder
+	builder.WriteString("✅ 历法转换成功！\n\n【转换结果】\n")
+	fmt.Fprintf(&builder, "公历：%d年%d月%d日 星期%s\n", solar.Year(), solar.Month(), solar.Day(), weekdays[solar.Weekday()])
+	fmt.Fprintf(&builder, "农历：%s年（%s年）%s%s\n", bazi.JiaZi(yearIndex), bazi.ShengXiao[yearIndex%12], lunar.MonthName(), lunar.DayName())
+
+	builder.WriteString("\n【农历月份信息】\n")
+	size := "小月"
+	if days == 30 {
+		size = "大月"
+	}
+	fmt.Fprintf(&builder, "本月天数：%d天（%s）\n", days, size)
+	if leap := calendar.LeapMonth(lunar.Year); leap > 0 {
+		fmt.Fprintf(&builder, "农历%d年闰月：%s\n", lunar.Year, calendar.LunarDate{Month: leap, Leap: true}.MonthName())
+	} else {
+		fmt.Fprintf(&builder, "农历%d年闰月：无\n", lunar.Year)
+	}
+	builder.WriteString("\n说明：农历按定朔定气规则以北京时间推算，排盘时请以公历日期或带闰月标记的农历日期输入。")
+
+	return builder.String(), false, nil
+}
@@ -0,0 +1,39 @@
+package application
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/calendar"
+)
+
+func TestCalendarConvert(t *testing.T) {
+	service := NewCalendarAppService()
+	tests := []struct {
+		name    string
+		req     calendar.ConvertRequest
+		want    []string
+		wantErr bool
+	}{
+		{"公历转农历", calendar.ConvertRequest{Type: 1, Year: 2000, Month: 1, Day: 2}, []string{"农历：己卯年（兔年）十一月廿六", "星期日"}, false},
+		{"农历闰月转公历", calendar.ConvertRequest{Type: 0, Year: 2023, Month: 2, Day: 1, Leap: true}, []string{"公历：2023年3月22日", "闰月：闰二月"}, false},
+		{"不存在的闰月", calendar.ConvertRequest{Type: 0, Year: 2024, Month: 2, Day: 1, Leap: true}, []string{"没有闰二月"}, true},
+		{"无效公历日期", calendar.ConvertRequest{Type: 1, Year: 2023, Month: 2, Day: 30}, []string{"历法转换失败"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, isError, err := service.Convert(tt.req)
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if isError != tt.wantErr {
+				t.Errorf("Convert() isError = %v, want %v: %s", isError, tt.wantErr, text)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("Convert() 结果缺少 %q:\n%s", want, text)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package application
+
+import (
+	"fmt"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// ChartSchemaURI 结构化排盘结果 JSON Schema 资源的 URI
+const ChartSchemaURI = "data://schema/chart"
+
+// chartSchema 结构化排盘结果的 JSON Schema 文档
+type chartSchema struct {
+	Schema      string `json:"$schema"`
+	ID          string `json:"$id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	protocol.InputSchema
+}
+
+// ChartSchemaJSON 返回 bazi.Chart 的 JSON Schema，字段说明取自结构体的 description 标签
+func ChartSchemaJSON() (string, error) {
+	tool, err := protocol.NewTool("chart", "", bazi.Chart{})
+	if err != nil {
+		return "", fmt.Errorf("生成排盘结果 JSON Schema 失败: %w", err)
+	}
+	return marshalResource(chartSchema{
+		Schema:      "https://json-schema.org/draft/2020-12/schema",
+		ID:          ChartSchemaURI,
+		Title:       "八字排盘结果",
+		Description: "bazi_paipan 在 output 为 json 或 both 时返回的结构化排盘结果：四柱为对象，大运为按时间排序的数组，流年嵌套在所属大运中",
+		InputSchema: tool.InputSchema,
+	})
+}
+
+// JSON 返回结构化排盘结果的 JSON 文本
+func (r PaipanResult) JSON() (string, error) {
+	if r.Chart == nil {
+		return "", fmt.Errorf("无结构化排盘结果")
+	}
+	return marshalResource(r.Chart)
+}
@@ -0,0 +1,104 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+	"github.com/justinwongcn/bazi-mcp/internal/domain/calendar"
+	"github.com/justinwongcn/bazi-mcp/internal/domain/location"
+)
+
+// adjustHistoricalClock 出生于 1986-1991 年夏令时或民国分区时制期间时，将钟表时间换算为北京时间（UTC+8 标准时），
+// 返回换算说明。显式填写时区或出生在境外、港澳台时不做处理。
+func (s *BaziAppService) adjustHistoricalClock(req *bazi.Request) string {
+	if req.Timezone != "" || (req.Province == "" && req.City != "" && req.Longitude == nil) {
+		return ""
+	}
+	if req.Province != "" && !location.FollowsBeijingTime(req.Province) {
+		return ""
+	}
+	clock, ok := solarClock(*req)
+	if !ok {
+		return ""
+	}
+
+	var builder strings.Builder
+	var offset time.Duration
+	if dst, ambiguity, ok := calendar.ChinaDaylightSaving(clock); ok {
+		offset = time.Hour
+		fmt.Fprintf(&builder, "夏令时：%s 期间钟表拨快1小时，已%s\n", dst, describeShift(-offset))
+		switch ambiguity {
+		case calendar.ClockSkipped:
+			builder.WriteString("⚠️ 该时刻在夏令时开始时被跳过（2时直接拨至3时），按夏令时处理，请核对出生时间\n")
+		case calendar.ClockRepeated:
+			builder.WriteString("⚠️ 该时刻在夏令时结束时出现两次（2时拨回1时），按第一次（夏令时）处理，请核对出生时间\n")
+		}
+	} else if zone, ok := location.HistoricalZoneOf(s.historicalPlace(*req), clock); ok && zone.Offset != 8*time.Hour {
+		offset = zone.Offset - 8*time.Hour
+		fmt.Fprintf(&builder, "分区时制：1949年以前%s属%s（标准经线%s，%s），已%s换算为北京时间\n",
+			req.Province, zone.Name, formatLongitude(zone.Meridian), formatOffset(zone.Offset), describeShift(-offset))
+		builder.WriteString("注：分区时制按省级行政区近似划分，当地实际用时可能有出入\n")
+	}
+	if offset == 0 {
+		return ""
+	}
+
+	adjusted := clock.Add(-offset)
+	setSolarClock(req, adjusted)
+	return fmt.Sprintf("\n【历史时制校正】\n钟表时间：%s\n%s北京时间：%s\n", formatClock(clock), builder.String(), formatClock(adjusted))
+}
+
+// historicalPlace 返回用于判断分区时制的出生地，未填写城市时以省会代替
+func (s *BaziAppService) historicalPlace(req bazi.Request) location.Place {
+	if s.Gazetteer != nil {
+		if place, ok := s.Gazetteer.Lookup(req.Province, req.City); ok {
+			return place
+		}
+		if seat, ok := s.Gazetteer.Seat(req.Province); ok {
+			return seat
+		}
+	}
+	return location.Place{Province: req.Province, City: req.City}
+}
+
+// solarClock 返回请求的公历钟表时间，农历日期先换算为公历；日期无效时返回 false，由后续步骤提示
+func solarClock(req bazi.Request) (time.Time, bool) {
+	if req.Type != 0 {
+		return time.Date(req.Year, time.Month(req.Month), req.Day, req.Hours, req.Minute, 0, 0, beijing), true
+	}
+	date, err := calendar.LunarToSolar(req.Year, req.Month, req.Day, req.Leap)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), req.Hours, req.Minute, 0, 0, beijing), true
+}
+
+// setSolarClock 将公历钟表时间写回请求，农历输入仍以农历日期表示
+func setSolarClock(req *bazi.Request, clock time.Time) {
+	req.Hours, req.Minute = clock.Hour(), clock.Minute()
+	if req.Type != 0 {
+		req.Year, req.Month, req.Day = clock.Year(), int(clock.Month()), clock.Day()
+		return
+	}
+	if lunar, err := calendar.SolarToLunar(clock.Year(), int(clock.Month()), clock.Day()); err == nil {
+		req.Year, req.Month, req.Day, req.Leap = lunar.Year, lunar.Month, lunar.Day, lunar.Leap
+	}
+}
+
+// describeShift 将时间调整量描述为“加1小时”“减30分钟”
+func describeShift(d time.Duration) string {
+	verb := "加"
+	if d < 0 {
+		verb, d = "减", -d
+	}
+	hours, minutes := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch {
+	case minutes == 0:
+		return fmt.Sprintf("%s%d小时", verb, hours)
+	case hours == 0:
+		return fmt.Sprintf("%s%d分钟", verb, minutes)
+	}
+	return fmt.Sprintf("%s%d小时%d分钟", verb, hours, minutes)
+}
@@ -0,0 +1,108 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// NamedService 具名的排盘提供方，用于跨提供方比对
+type NamedService struct {
+	Name    string
+	Service bazi.Service
+}
+
+// Comparison 同一请求在两个提供方上的排盘结果比对
+type Comparison struct {
+	Left        string            `json:"left"`             // 左侧提供方
+	Right       string            `json:"right"`            // 右侧提供方
+	Consistent  bool              `json:"consistent"`       // 两侧均排盘成功且没有差异
+	Errors      map[string]string `json:"errors,omitempty"` // 排盘失败的提供方及其错误
+	Differences []bazi.Difference `json:"differences"`      // 不一致的字段
+}
+
+// Compare 以同样的换算后请求分别调用 CompareProviders 中的两个提供方，比较规范化后的四柱、十神、大运与神煞。
+// 输入有误时返回错误说明文本；某一侧排盘失败时记录在 Errors 中，不视为调用错误。
+func (s *BaziAppService) Compare(ctx context.Context, req bazi.Request) (Comparison, string, bool) {
+	if len(s.CompareProviders) < 2 {
+		return Comparison{}, "未配置用于比对的两个排盘提供方", true
+	}
+	left, right := s.CompareProviders[0], s.CompareProviders[1]
+	if _, errMsg, hasError := s.validateInput(&req); hasError {
+		return Comparison{}, errMsg, true
+	}
+	if _, errMsg, hasError := s.prepareChartRequest(&req); hasError {
+		return Comparison{}, errMsg, true
+	}
+
+	result := Comparison{Left: left.Name, Right: right.Name, Differences: []bazi.Difference{}}
+	leftChart, leftErr := chartFrom(ctx, left.Service, req)
+	rightChart, rightErr := chartFrom(ctx, right.Service, req)
+	for name, err := range map[string]error{left.Name: leftErr, right.Name: rightErr} {
+		if err != nil {
+			if result.Errors == nil {
+				result.Errors = make(map[string]string)
+			}
+			result.Errors[name] = err.Error()
+		}
+	}
+	if leftErr == nil && rightErr == nil {
+		result.Differences = bazi.DiffCharts(leftChart, rightChart)
+		result.Consistent = len(result.Differences) == 0
+	}
+	return result, "", false
+}
+
+// chartFrom 调用提供方并将结果规范化为 Chart，业务错误按错误码与说明返回
+func chartFrom(ctx context.Context, service bazi.Service, req bazi.Request) (bazi.Chart, error) {
+	resp, err := service.GetPaipanResult(ctx, req)
+	var providerErr *bazi.ProviderError
+	if errors.As(err, &providerErr) && providerErr.Kind == bazi.ErrorBusiness {
+		resp, err = &bazi.PaipanResponse{ErrCode: providerErr.ErrCode, ErrMsg: providerErr.Message}, nil
+	}
+	if err != nil {
+		return bazi.Chart{}, err
+	}
+	if resp.ErrCode != 0 {
+		return bazi.Chart{}, fmt.Errorf("错误码 %d: %s", resp.ErrCode, resp.ErrMsg)
+	}
+	return bazi.NewChart(resp.Data)
+}
+
+// Text 返回比对结果的摘要
+func (c Comparison) Text() string {
+	var b strings.Builder
+	for _, name := range []string{c.Left, c.Right} {
+		if msg, ok := c.Errors[name]; ok {
+			fmt.Fprintf(&b, "%s 排盘失败: %s\n", name, msg)
+		}
+	}
+	switch {
+	case len(c.Errors) > 0:
+		b.WriteString("无法比对排盘结果\n")
+	case c.Consistent:
+		fmt.Fprintf(&b, "%s 与 %s 的排盘结果一致\n", c.Left, c.Right)
+	default:
+		fmt.Fprintf(&b, "%s 与 %s 的排盘结果有 %d 处不一致：\n", c.Left, c.Right, len(c.Differences))
+		for _, d := range c.Differences {
+			fmt.Fprintf(&b, "- %s：%s ≠ %s\n", d.Field, orNone(d.Left), orNone(d.Right))
+		}
+	}
+	return b.String()
+}
+
+// JSON 返回比对结果的 JSON 文本
+func (c Comparison) JSON() (string, error) {
+	return marshalResource(c)
+}
+
+// orNone 将空值显示为“无”
+func orNone(s string) string {
+	if s == "" {
+		return "无"
+	}
+	return s
+}
@@ -0,0 +1,72 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+func TestCompare(t *testing.T) {
+	req := bazi.Request{Name: "张三", Sex: 0, Type: 1, Year: 2000, Month: 1, Day: 2, Hours: 3, Minute: 4}
+	newService := func(left, right *stubService) *BaziAppService {
+		service := NewBaziAppService(nil, nil, nil)
+		service.CompareProviders = []NamedService{{Name: "yuanfenju", Service: left}, {Name: "local", Service: right}}
+		return service
+	}
+
+	t.Run("一致", func(t *testing.T) {
+		left, right := &stubService{resp: loadTestData(t)}, &stubService{resp: loadTestData(t)}
+		result, errMsg, isError := newService(left, right).Compare(context.Background(), req)
+		if isError {
+			t.Fatalf("Compare() 错误: %s", errMsg)
+		}
+		if !result.Consistent || len(result.Differences) != 0 || !strings.Contains(result.Text(), "排盘结果一致") {
+			t.Errorf("Compare() = %+v", result)
+		}
+		if left.req != right.req {
+			t.Errorf("两侧的排盘请求应相同: %+v, %+v", left.req, right.req)
+		}
+	})
+
+	t.Run("有差异", func(t *testing.T) {
+		changed := loadTestData(t)
+		changed.Data.DetailInfo.Zhuxing.Year = "正官"
+		changed.Data.DayunInfo.BigStartYear[0]++
+		result, _, _ := newService(&stubService{resp: loadTestData(t)}, &stubService{resp: changed}).Compare(context.Background(), req)
+		if result.Consistent || len(result.Differences) != 2 {
+			t.Fatalf("Compare() = %+v", result)
+		}
+		if d := result.Differences[0]; d.Field != "pillars.year.ten_god" || d.Right != "正官" {
+			t.Errorf("四柱十神差异 = %+v", d)
+		}
+		if d := result.Differences[1]; d.Field != "dayun[0].start_year" {
+			t.Errorf("大运起运年份差异 = %+v", d)
+		}
+
+		text, err := result.JSON()
+		if err != nil {
+			t.Fatalf("JSON() error = %v", err)
+		}
+		var decoded Comparison
+		if err := json.Unmarshal([]byte(text), &decoded); err != nil || len(decoded.Differences) != 2 {
+			t.Errorf("JSON() = %s, %v", text, err)
+		}
+	})
+
+	t.Run("一侧失败", func(t *testing.T) {
+		result, _, isError := newService(&stubService{err: errors.New("connection refused")}, &stubService{resp: loadTestData(t)}).Compare(context.Background(), req)
+		if isError || result.Consistent || result.Errors["yuanfenju"] == "" || !strings.Contains(result.Text(), "yuanfenju 排盘失败") {
+			t.Errorf("Compare() = %+v", result)
+		}
+	})
+
+	t.Run("未配置", func(t *testing.T) {
+		if _, _, isError := NewBaziAppService(nil, nil, nil).Compare(context.Background(), req); !isError {
+			t.Error("未配置比对提供方时应返回错误")
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/location"
+)
+
+// CitiesURIPrefix 城市列表资源 URI 的前缀，后接省级行政区名称
+const CitiesURIPrefix = "data://cities/"
+
+// ErrUnknownProvince 查询城市列表时省份无效
+var ErrUnknownProvince = errors.New("无效省份")
+
+// ProvinceInfo 省级行政区列表中的一项
+type ProvinceInfo struct {
+	Name      string `json:"name"`           // 省级行政区名称，可直接用作 province 参数
+	Code      string `json:"code,omitempty"` // 省级行政区划代码
+	Seat      string `json:"seat,omitempty"` // 省会（首府）
+	CityCount int    `json:"city_count"`     // 下辖地点数量
+	CitiesURI string `json:"cities_uri"`     // 城市列表资源 URI
+}
+
+// ProvinceList data://provinces 资源的内容
+type ProvinceList struct {
+	Count     int            `json:"count"`
+	Provinces []ProvinceInfo `json:"provinces"`
+}
+
+// CityInfo 城市列表中的一项
+type CityInfo struct {
+	Name        string   `json:"name"`                  // 规范地名，可直接用作 city 参数
+	Code        string   `json:"code,omitempty"`        // 县级行政区划代码
+	Longitude   float64  `json:"longitude"`             // 经度
+	Latitude    float64  `json:"latitude"`              // 纬度
+	Approximate bool     `json:"approximate,omitempty"` // 坐标未收录，以省会坐标近似
+	Aliases     []string `json:"aliases,omitempty"`     // 同样可以识别的今名、旧名、市辖区等别名
+}
+
+// CityList data://cities/{province} 资源的内容
+type CityList struct {
+	Province string     `json:"province"`
+	Count    int        `json:"count"`
+	Cities   []CityInfo `json:"cities"`
+}
+
+// LocationAppService 定义了出生地查询的应用服务。
+type LocationAppService struct {
+	Gazetteer *location.Gazetteer // 地名录，为空时仅返回 location.Provinces 与 location.Cities 中的名称
+}
+
+// NewLocationAppService 创建一个新的 LocationAppService 实例。
+func NewLocationAppService(gazetteer *location.Gazetteer) *LocationAppService {
+	return &LocationAppService{Gazetteer: gazetteer}
+}
+
+// Provinces 返回全部省级行政区
+func (s *LocationAppService) Provinces() ProvinceList {
+	if s.Gazetteer == nil {
+		list := ProvinceList{Provinces: make([]ProvinceInfo, 0, len(location.Provinces))}
+		for _, name := range location.Provinces {
+			list.Provinces = append(list.Provinces, ProvinceInfo{Name: name, CityCount: len(location.Cities[name]), CitiesURI: CitiesURIPrefix + name})
+		}
+		list.Count = len(list.Provinces)
+		return list
+	}
+
+	provinces := s.Gazetteer.Provinces()
+	list := ProvinceList{Count: len(provinces), Provinces: make([]ProvinceInfo, 0, len(provinces))}
+	for _, name := range provinces {
+		seat, _ := s.Gazetteer.Seat(name)
+		list.Provinces = append(list.Provinces, ProvinceInfo{
+			Name:      name,
+			Code:      s.Gazetteer.Code(name),
+			Seat:      seat.City,
+			CityCount: len(s.Gazetteer.Cities(name)),
+			CitiesURI: CitiesURIPrefix + name,
+		})
+	}
+	return list
+}
+
+// Cities 返回省级行政区下辖的地点，省份名称支持模糊匹配
+func (s *LocationAppService) Cities(province string) (CityList, error) {
+	matchProvince := location.MatchProvince
+	if s.Gazetteer != nil {
+		matchProvince = s.Gazetteer.MatchProvince
+	}
+	matched, ratio := matchProvince(province)
+	if ratio < 0.6 {
+		return CityList{}, fmt.Errorf("%w: %s，可通过 data://provinces 查看省份列表", ErrUnknownProvince, province)
+	}
+
+	list := CityList{Province: matched}
+	if s.Gazetteer == nil {
+		for _, name := range location.Cities[matched] {
+			list.Cities = append(list.Cities, CityInfo{Name: name})
+		}
+	} else {
+		for _, name := range s.Gazetteer.Cities(matched) {
+			place, _ := s.Gazetteer.Lookup(matched, name)
+			city := CityInfo{Name: name, Code: place.Code, Longitude: place.Longitude, Latitude: place.Latitude, Approximate: place.Approximate}
+			for _, alias := range s.Gazetteer.Aliases(matched, name) {
+				city.Aliases = append(city.Aliases, alias.Name)
+			}
+			sort.Strings(city.Aliases)
+			list.Cities = append(list.Cities, city)
+		}
+	}
+	list.Count = len(list.Cities)
+	return list, nil
+}
+
+// ProvincesJSON 返回省级行政区列表的 JSON 文本
+func (s *LocationAppService) ProvincesJSON() (string, error) {
+	return marshalResource(s.Provinces())
+}
+
+// CitiesJSON 返回城市列表的 JSON 文本
+func (s *LocationAppService) CitiesJSON(province string) (string, error) {
+	list, err := s.Cities(province)
+	if err != nil {
+		return "", err
+	}
+	return marshalResource(list)
+}
+
+// marshalResource 将资源内容编码为缩进的 JSON
+func marshalResource(v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("编码资源内容失败: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,70 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/location"
+)
+
+func TestLocationAppService(t *testing.T) {
+	gazetteer, err := location.NewGazetteer([]location.Division{
+		{
+			Name: "北京市", Code: "110000", Seat: "北京",
+			Places: []location.Place{
+				{City: "北京", Code: "110000", Longitude: 116.41, Latitude: 39.90},
+				{City: "通县", Code: "110112", Longitude: 116.65, Latitude: 39.92},
+			},
+			Aliases: []location.Alias{
+				{Name: "海淀", City: "北京", Kind: location.AliasDistrict},
+				{Name: "朝阳", City: "北京", Kind: location.AliasDistrict},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewGazetteer() error = %v", err)
+	}
+	service := NewLocationAppService(gazetteer)
+
+	t.Run("省份列表", func(t *testing.T) {
+		text, err := service.ProvincesJSON()
+		if err != nil {
+			t.Fatalf("ProvincesJSON() error = %v", err)
+		}
+		var list ProvinceList
+		if err := json.Unmarshal([]byte(text), &list); err != nil {
+			t.Fatalf("应返回合法 JSON: %v", err)
+		}
+		want := ProvinceInfo{Name: "北京市", Code: "110000", Seat: "北京", CityCount: 2, CitiesURI: "data://cities/北京市"}
+		if list.Count != 1 || list.Provinces[0] != want {
+			t.Errorf("ProvincesJSON() = %+v", list)
+		}
+	})
+
+	t.Run("城市列表", func(t *testing.T) {
+		list, err := service.Cities("北京")
+		if err != nil {
+			t.Fatalf("Cities() error = %v", err)
+		}
+		if list.Province != "北京市" || list.Count != 2 || list.Cities[0].Name != "北京" || list.Cities[1].Code != "110112" {
+			t.Fatalf("Cities() = %+v", list)
+		}
+		if aliases := list.Cities[0].Aliases; len(aliases) != 2 || aliases[0] != "朝阳" {
+			t.Errorf("别名应排序列出, got %v", aliases)
+		}
+	})
+
+	t.Run("无效省份", func(t *testing.T) {
+		if _, err := service.CitiesJSON("火星"); !errors.Is(err, ErrUnknownProvince) {
+			t.Errorf("CitiesJSON() error = %v, want ErrUnknownProvince", err)
+		}
+	})
+
+	t.Run("无地名录时使用内置列表", func(t *testing.T) {
+		list := NewLocationAppService(nil).Provinces()
+		if list.Count != len(location.Provinces) {
+			t.Errorf("Provinces() count = %d, want %d", list.Count, len(location.Provinces))
+		}
+	})
+}
@@ -2,71 +2,293 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv" // 添加 strconv 包
 	"strings"
+	"time"
 
 	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+	"github.com/justinwongcn/bazi-mcp/internal/domain/calendar"
 	"github.com/justinwongcn/bazi-mcp/internal/domain/location"
 )
 
 var pillars = []string{"年", "月", "日", "时"}
 
+// beijing 北京时间（UTC+8），排盘以北京时间为准
+var beijing = time.FixedZone("CST", 8*3600)
+
 // BaziAppService 定义了八字排盘的应用服务。
 type BaziAppService struct {
 	BaziDomainService bazi.Service
+	Gazetteer         *location.Gazetteer      // 地名录，用于查询出生地坐标，为空时不在本地校正真太阳时
+	WorldGazetteer    *location.WorldGazetteer // 境外城市地名录，用于查询境外出生地的坐标与时区
+	CompareProviders  []NamedService           // 跨提供方比对的两个提供方，未配置时不提供比对
+	Defaults          bazi.RequestDefaults     // 请求中未填写的流派、真太阳时与语言的默认值
+	Schools           []bazi.School            // 命局分析中取用神的流派，按优先顺序排列，为空时按扶抑
 }
 
 // NewBaziAppService 创建一个新的 BaziAppService 实例。
-func NewBaziAppService(baziDomainService bazi.Service) *BaziAppService {
+func NewBaziAppService(baziDomainService bazi.Service, gazetteer *location.Gazetteer, worldGazetteer *location.WorldGazetteer) *BaziAppService {
 	return &BaziAppService{
 		BaziDomainService: baziDomainService,
+		Gazetteer:         gazetteer,
+		WorldGazetteer:    worldGazetteer,
 	}
 }
 
+// PaipanResult 排盘结果的文本与结构化两种形式
+type PaipanResult struct {
+	Text    string      // 排盘文本，出错时为错误说明
+	Chart   *bazi.Chart // 结构化排盘结果，仅排盘成功时非空
+	IsError bool        // 是否为业务错误
+}
+
 // GetBaziPaipan 处理获取八字排盘结果的请求。
 func (s *BaziAppService) GetBaziPaipan(ctx context.Context, req bazi.Request) (string, bool, error) {
+	result, err := s.Paipan(ctx, req)
+	return result.Text, result.IsError, err
+}
+
+// Paipan 排盘并同时返回文本与结构化结果
+func (s *BaziAppService) Paipan(ctx context.Context, req bazi.Request) (PaipanResult, error) {
+	baziResp, notes, errMsg, err := s.fetchPaipan(ctx, &req)
+	if errMsg != "" {
+		return PaipanResult{Text: errMsg, IsError: true}, nil
+	}
+	if err != nil {
+		// 底层错误，直接返回
+		return PaipanResult{IsError: true}, err
+	}
+
+	// 处理API响应
+	text, isError, err := s.handleAPIResponse(req, baziResp)
+	if err != nil {
+		return PaipanResult{IsError: true}, err
+	}
+	result := PaipanResult{Text: text + strings.Join(notes, ""), IsError: isError}
+	if !isError {
+		chart, err := bazi.NewChart(baziResp.Data)
+		if err != nil {
+			return PaipanResult{IsError: true}, fmt.Errorf("规范化排盘结果失败: %w", err)
+		}
+		for _, note := range notes {
+			chart.Notes = append(chart.Notes, strings.TrimSpace(note))
+		}
+		if baziResp.Provider != "" {
+			chart.Provider = baziResp.Provider
+			result.Text += fmt.Sprintf("\n【排盘来源】\n本次排盘由 %s 提供\n", baziResp.Provider)
+		}
+		result.Chart = &chart
+	}
+	return result, nil
+}
+
+// fetchPaipan 校验输入并设置默认值，在本地换算后调用领域服务获取排盘结果。
+// 输入有误时返回错误说明；提供方的业务错误转换为带错误码的响应，与响应中的错误码一样作为输入错误提示给用户
+func (s *BaziAppService) fetchPaipan(ctx context.Context, req *bazi.Request) (resp *bazi.PaipanResponse, notes []string, errMsg string, err error) {
 	// 输入验证和默认值设置
-	if errMsg, hasError := s.validateInput(req); hasError {
-		return errMsg, true, nil
+	placeNote, errMsg, hasError := s.validateInput(req)
+	if hasError {
+		return nil, nil, errMsg, nil
 	}
 	// 其他默认值在 APIClient 或请求构建时处理，这里主要处理业务逻辑相关的默认值或校验
 
-	// 3. 调用领域服务获取结果
-	baziResp, err := s.BaziDomainService.GetPaipanResult(ctx, req)
+	// 农历日期与真太阳时在本地换算，提供方仅接收换算后的公历钟表时间
+	chartReq := *req
+	notes, errMsg, hasError = s.prepareChartRequest(&chartReq)
+	if hasError {
+		return nil, nil, errMsg, nil
+	}
+	if placeNote != "" {
+		notes = append([]string{placeNote}, notes...)
+	}
+
+	// 调用领域服务获取结果
+	resp, err = s.BaziDomainService.GetPaipanResult(ctx, chartReq)
+	var providerErr *bazi.ProviderError
+	if errors.As(err, &providerErr) && providerErr.Kind == bazi.ErrorBusiness {
+		resp, err = &bazi.PaipanResponse{ErrCode: providerErr.ErrCode, ErrMsg: providerErr.Message}, nil
+	}
 	if err != nil {
-		// 底层错误，直接返回
-		return "", true, fmt.Errorf("获取八字结果失败: %w", err)
+		return nil, nil, "", fmt.Errorf("获取八字结果失败: %w", err)
 	}
+	return resp, notes, "", nil
+}
 
-	// 处理API响应
-	return s.handleAPIResponse(req, baziResp)
+// prepareChartRequest 依次完成农历换算与真太阳时校正（或时区换算），返回各步骤的说明文本
+func (s *BaziAppService) prepareChartRequest(req *bazi.Request) (notes []string, errMsg string, hasError bool) {
+	note, errMsg, hasError := s.normalizeLunarDate(req)
+	if hasError {
+		return nil, errMsg, true
+	}
+	if note != "" {
+		notes = append(notes, note)
+	}
+	if note := s.applyTrueSolarTime(req); note != "" {
+		notes = append(notes, note)
+	} else if note := s.convertTimeZone(req); note != "" {
+		notes = append(notes, note)
+	}
+	return notes, "", false
 }
 
-// validateInput 验证输入参数并设置默认值
-func (s *BaziAppService) validateInput(req bazi.Request) (string, bool) {
-	// 1. 输入验证 (省份和城市有效性)
+// validateInput 验证输入参数并设置默认值，出生地按别名或模糊匹配改写为规范地名时返回匹配说明
+func (s *BaziAppService) validateInput(req *bazi.Request) (note, errMsg string, hasError bool) {
+	// 1. 输入验证 (输出格式、出生地坐标、时区、省份和城市有效性)
+	if !bazi.ValidOutput(req.Output) {
+		return "", fmt.Sprintf("无效输出格式: %s\n 可选 text（排盘文本）、json（结构化 JSON）、both（两者）", req.Output), true
+	}
+	if errMsg, hasError := validateBirthplace(req); hasError {
+		return "", errMsg, true
+	}
 	if req.Province != "" {
-		matchedProvince, ratio := location.MatchProvince(req.Province)
+		matchProvince := location.MatchProvince
+		if s.Gazetteer != nil {
+			matchProvince = s.Gazetteer.MatchProvince
+		}
+		matchedProvince, ratio := matchProvince(req.Province)
 		if ratio < 0.6 {
-			return fmt.Sprintf("无效省份: %s\n 一般最后面需要带上“省市区”等 例：\"北京市\"", req.Province), true
+			return "", fmt.Sprintf("无效省份: %s\n 一般最后面需要带上“省市区”等 例：\"北京市\"", req.Province), true
 		}
 		req.Province = matchedProvince
 	}
-	if req.City != "" {
+	switch {
+	case req.City == "":
+	case req.Longitude != nil && req.Province == "":
+		// 显式填写坐标时 city 仅作为出生地名称展示，不做匹配
+	case s.Gazetteer == nil:
 		matchedCity, ratio := location.MatchCity(req.City, req.Province)
 		if ratio < 0.5 {
-			return fmt.Sprintf("无效城市: %s\n 最后面一般不带上“县市区”等（除非带上后只有两个字）", req.City), true
+			return "", fmt.Sprintf("无效城市: %s\n 最后面一般不带上“县市区”等（除非带上后只有两个字）", req.City), true
 		}
 		req.City = matchedCity
+	default:
+		m, ok := s.Gazetteer.MatchCity(req.City, req.Province)
+		if !ok && req.Province == "" {
+			// 未填写省份且国内无唯一匹配时，按境外城市查找
+			if note, ok = s.matchWorldCity(req); ok {
+				break
+			}
+		}
+		if !ok || m.Score < 0.5 {
+			return "", fmt.Sprintf("无效城市: %s\n 可使用今名、旧名或市辖区名称，如“通州”“海淀”；境外城市可填写中英文名称，如“纽约”“London”，或直接填写经纬度与时区；最后面一般不带上“县市区”等（除非带上后只有两个字）", req.City), true
+		}
+		if m.Reason != location.MatchExact || m.Input != m.Place.City || req.Province == "" {
+			note = fmt.Sprintf("\n【出生地匹配】\n%s\n", m.Explain())
+		}
+		req.Province, req.City = m.Place.Province, m.Place.City
 	}
 
-	// 2. 设置默认值 (如果请求中未提供)
+	// 2. 夏令时与民国分区时制换算为北京时间
+	note += s.adjustHistoricalClock(req)
+
+	// 3. 设置默认值 (如果请求中未提供)
 	if req.Name == "" {
 		req.Name = "求测者"
 	}
+	s.Defaults.Apply(req)
 
-	return "", false
+	return note, "", false
+}
+
+// normalizeLunarDate 将农历出生日期（含闰月）转换为公历，返回转换说明或错误提示
+func (s *BaziAppService) normalizeLunarDate(req *bazi.Request) (note, errMsg string, hasError bool) {
+	if req.Type != 0 {
+		req.Leap = false
+		return "", "", false
+	}
+
+	lunar := calendar.LunarDate{Year: req.Year, Month: req.Month, Day: req.Day, Leap: req.Leap}
+	solar, err := calendar.LunarToSolar(req.Year, req.Month, req.Day, req.Leap)
+	if err != nil {
+		msg := fmt.Sprintf("无效的农历出生日期: %v", err)
+		if req.Year < calendar.MinYear || req.Year > calendar.MaxYear {
+			return "", msg, true
+		}
+		if leap := calendar.LeapMonth(req.Year); leap > 0 {
+			msg += fmt.Sprintf("\n农历%d年的闰月为%s，如出生在闰月请设置 leap=true", req.Year, calendar.LunarDate{Month: leap, Leap: true}.MonthName())
+		}
+		return "", msg, true
+	}
+
+	req.Type = 1
+	req.Leap = false
+	req.Year, req.Month, req.Day = solar.Year(), int(solar.Month()), solar.Day()
+	note = fmt.Sprintf("\n【历法转换】\n输入的农历%s已换算为公历%d年%d月%d日后排盘\n",
+		lunar, req.Year, req.Month, req.Day)
+	return note, "", false
+}
+
+// applyTrueSolarTime 在 zhen=1 且出生地坐标可查时，按经度时差与均时差将出生时间校正为真太阳时，
+// 并改为以 zhen=2 请求提供方，避免重复校正。坐标不可查时保持原请求，由提供方处理。
+// 境外出生地按当地时区的钟表时间计算，校正结果即出生地的真太阳时。
+func (s *BaziAppService) applyTrueSolarTime(req *bazi.Request) string {
+	if req.Zhen != 1 {
+		return ""
+	}
+	place, ok := s.resolveBirthplace(*req)
+	if !ok {
+		return ""
+	}
+
+	civil, warning := localCivilTime(*req, place.zone)
+	solar := calendar.NewTrueSolarTime(civil, place.longitude)
+	corrected := solar.Corrected.Truncate(time.Minute)
+
+	req.Zhen = 2
+	req.Year, req.Month, req.Day = corrected.Year(), int(corrected.Month()), corrected.Day()
+	req.Hours, req.Minute = corrected.Hour(), corrected.Minute()
+
+	var builder strings.Builder
+	builder.WriteString("\n【真太阳时校正】\n")
+	fmt.Fprintf(&builder, "出生地：%s（%s", place.label, formatCoordinate(place.longitude, place.latitude))
+	if place.approximate != "" {
+		fmt.Fprintf(&builder, "，%s", place.approximate)
+	}
+	builder.WriteString("）\n")
+	if place.zone != beijing {
+		fmt.Fprintf(&builder, "当地时区：%s（%s）\n", place.zone, formatUTCOffset(civil))
+	}
+	fmt.Fprintf(&builder, "钟表时间：%s\n", formatClock(solar.Civil))
+	builder.WriteString(warning)
+	fmt.Fprintf(&builder, "经度时差：%s（中央经线%s）\n", formatSeconds(solar.LongitudeSeconds), formatLongitude(solar.Meridian))
+	fmt.Fprintf(&builder, "均时差：%s\n", formatSeconds(solar.EquationSeconds))
+	fmt.Fprintf(&builder, "总校正：%s\n", formatSeconds(solar.CorrectionSeconds()))
+	fmt.Fprintf(&builder, "真太阳时：%s（排盘取 %d时%d分）\n", formatClock(solar.Corrected), req.Hours, req.Minute)
+
+	rawBranch, correctedBranch := hourBranch(civil), hourBranch(corrected)
+	if rawBranch != correctedBranch {
+		fmt.Fprintf(&builder, "时辰：%s时 → %s时（跨越时辰边界，时柱随之改变）\n", bazi.DiZhi[rawBranch], bazi.DiZhi[correctedBranch])
+	} else {
+		fmt.Fprintf(&builder, "时辰：%s时（未跨越时辰边界）\n", bazi.DiZhi[correctedBranch])
+	}
+	if civil.YearDay() != corrected.YearDay() {
+		builder.WriteString("⚠️ 校正后出生日期发生变化，日柱以真太阳时日期为准\n")
+	}
+	return builder.String()
+}
+
+// hourBranch 返回时刻所在时辰的地支序号（23 时起为子时）
+func hourBranch(t time.Time) int {
+	return (t.Hour() + 1) / 2 % 12
+}
+
+// formatClock 格式化到秒的钟表时间
+func formatClock(t time.Time) string {
+	return fmt.Sprintf("%d年%d月%d日%d时%d分%d秒", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+}
+
+// formatSeconds 将秒数格式化为带符号的“x分y秒”
+func formatSeconds(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	if seconds >= 3600 {
+		return fmt.Sprintf("%s%d时%d分%d秒", sign, seconds/3600, seconds%3600/60, seconds%60)
+	}
+	return fmt.Sprintf("%s%d分%d秒", sign, seconds/60, seconds%60)
 }
 
 // handleAPIResponse 处理API响应
@@ -89,7 +311,10 @@ func (s *BaziAppService) handleAPIResponse(req bazi.Request, resp *bazi.PaipanRe
 	// 使用formatDetailedText格式化详细排盘数据
 	// 从 bazi.Result 中提取数据
 	// 并使用 formatDetailedText 格式化
-	formattedText := s.formatDetailedText(resp)
+	formattedText, err := s.formatDetailedText(resp)
+	if err != nil {
+		return "", true, fmt.Errorf("格式化排盘结果失败: %w", err)
+	}
 	promptText += formattedText
 
 	return promptText, false, nil
@@ -231,60 +456,42 @@ func (s *BaziAppService) writeBaziInfo(builder *strings.Builder, baziInfo *bazi.
 }
 
 // writeDayunInfo 输出大运信息
-func (s *BaziAppService) writeDayunInfo(builder *strings.Builder, dayunInfo *bazi.DayunInfo) {
+func (s *BaziAppService) writeDayunInfo(builder *strings.Builder, periods []bazi.DayunPeriod) {
 	builder.WriteString("\n【大运信息】\n")
 
-	for i := range dayunInfo.Big {
+	for i, period := range periods {
 		// 大运基本信息
 		builder.WriteString("\n第")
 		builder.WriteString(strconv.Itoa(i + 1))
 		builder.WriteString("个大运： ")
-		builder.WriteString(strconv.Itoa(dayunInfo.XuSui[i]))
+		builder.WriteString(strconv.Itoa(period.StartAge))
 		builder.WriteString("-")
-		builder.WriteString(strconv.Itoa(dayunInfo.XuSui[i] + 10))
+		builder.WriteString(strconv.Itoa(period.StartAge + 10))
 		builder.WriteString("岁（")
-		builder.WriteString(strconv.Itoa(dayunInfo.BigStartYear[i]))
+		builder.WriteString(strconv.Itoa(period.StartYear))
 		builder.WriteString("年-")
-		builder.WriteString(strconv.Itoa(dayunInfo.BigEndYear[i]))
+		builder.WriteString(strconv.Itoa(period.EndYear))
 		builder.WriteString("年）：\n")
 
 		// 大运详细信息
 		builder.WriteString("  大运干支：")
-		builder.WriteString(dayunInfo.Big[i])
+		builder.WriteString(period.Ganzhi)
 		builder.WriteString("\n")
 		builder.WriteString("  大运天干十神：")
-		builder.WriteString(dayunInfo.BigGod[i])
+		builder.WriteString(period.TenGod)
 		builder.WriteString("\n")
 		builder.WriteString("  大运长生衰旺：")
-		builder.WriteString(dayunInfo.BigCs[i])
+		builder.WriteString(period.Growth)
 		builder.WriteString("\n")
-		// 流年信息
-		type yearOffsetType struct {
-			yearChar   string
-			yearOffset int
-		}
-
-		yearsInfoFields := []yearOffsetType{
-			{dayunInfo.YearsInfo0[i].YearChar, -1},
-			{dayunInfo.YearsInfo1[i].YearChar, 0},
-			{dayunInfo.YearsInfo2[i].YearChar, 1},
-			{dayunInfo.YearsInfo3[i].YearChar, 2},
-			{dayunInfo.YearsInfo4[i].YearChar, 3},
-			{dayunInfo.YearsInfo5[i].YearChar, 4},
-			{dayunInfo.YearsInfo6[i].YearChar, 5},
-			{dayunInfo.YearsInfo7[i].YearChar, 6},
-			{dayunInfo.YearsInfo8[i].YearChar, 7},
-			{dayunInfo.YearsInfo9[i].YearChar, 8},
-		}
 
-		for _, info := range yearsInfoFields {
-			// 使用 strings.Builder 和 strconv.Itoa 优化字符串拼接
+		// 流年信息
+		for _, year := range period.Years {
 			builder.WriteString("  流年年柱：")
-			builder.WriteString(info.yearChar)
+			builder.WriteString(year.Ganzhi)
 			builder.WriteString(" ")
-			builder.WriteString(strconv.Itoa(dayunInfo.BigStartYear[i] + info.yearOffset))
+			builder.WriteString(strconv.Itoa(year.Year))
 			builder.WriteString("年(虚岁")
-			builder.WriteString(strconv.Itoa(dayunInfo.XuSui[i] + info.yearOffset))
+			builder.WriteString(strconv.Itoa(year.Age))
 			builder.WriteString(")\n")
 		}
 	}
@@ -455,11 +662,16 @@ func (s *BaziAppService) writeZhenSolarTimeInfo(builder *strings.Builder, zhen *
 	builder.WriteString("\n")
 }
 
-// formatDetailedText 格式化详细文本
-func (s *BaziAppService) formatDetailedText(data *bazi.PaipanResponse) string {
+// formatDetailedText 格式化详细文本，大运信息不一致时返回 *bazi.DayunError
+func (s *BaziAppService) formatDetailedText(data *bazi.PaipanResponse) (string, error) {
 	var builder strings.Builder
 	resData := data.Data
 
+	periods, err := bazi.NewDayunPeriods(resData.DayunInfo, resData.DetailInfo.Dayunshensha)
+	if err != nil {
+		return "", err
+	}
+
 	// 输出基本信息
 	s.writeBaseInfo(&builder, &resData.BaseInfo)
 
@@ -469,13 +681,27 @@ func (s *BaziAppService) formatDetailedText(data *bazi.PaipanResponse) string {
 	// 输出八字排盘信息
 	s.writeBaziInfo(&builder, &resData.BaziInfo)
 
+	// 日主强弱、五行分布、格局与原局刑冲合害，八字信息无效时 writeBaziInfo 已给出提示
+	if strength, err := bazi.AnalyzeStrength(resData.BaziInfo); err == nil {
+		writeStrength(&builder, strength)
+	}
+	if elements, err := bazi.AnalyzeElements(resData.BaziInfo); err == nil {
+		writeElements(&builder, elements)
+	}
+	if pattern, err := bazi.ClassifyPattern(resData.BaziInfo); err == nil {
+		writePattern(&builder, pattern, resData.BaseInfo.Zhengge)
+	}
+	if relations, err := bazi.NatalRelations(resData.BaziInfo); err == nil {
+		writeRelations(&builder, relations, nil)
+	}
+
 	// 输出大运信息
-	s.writeDayunInfo(&builder, &resData.DayunInfo)
+	s.writeDayunInfo(&builder, periods)
 
 	// 起运信息
 	s.writeStartInfo(&builder, &resData.StartInfo)
 
 	s.writeDetailInfo(&builder, resData.DetailInfo)
 
-	return builder.String()
+	return builder.String(), nil
 }
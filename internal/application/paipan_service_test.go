@@ -1,13 +1,16 @@
 package application
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+	"github.com/justinwongcn/bazi-mcp/internal/domain/location"
 )
 
 func loadTestData(t *testing.T) *bazi.PaipanResponse {
@@ -81,7 +84,10 @@ func TestFormatDetailedText(t *testing.T) {
 			NaYin:   []string{"城头土", "涧下水", "天上火", "炉中火"},
 		}
 
-		result := service.formatDetailedText(&validData)
+		result, err := service.formatDetailedText(&validData)
+		if err != nil {
+			t.Fatalf("formatDetailedText() error = %v", err)
+		}
 		fmt.Println(result)
 		if result == "" {
 			t.Error("格式化结果不应为空")
@@ -99,7 +105,7 @@ func TestFormatDetailedText(t *testing.T) {
 	t.Run("无效八字数据", func(t *testing.T) {
 		invalidData := *testData
 		invalidData.Data.BaziInfo.Bazi = []string{"己卯", "丙子"} // 只有2柱
-		result := service.formatDetailedText(&invalidData)
+		result, _ := service.formatDetailedText(&invalidData)
 		if !strings.Contains(result, "错误：未获取到有效的八字排盘数据") {
 			t.Error("对于无效八字数据应返回错误信息")
 		}
@@ -116,7 +122,7 @@ func TestFormatDetailedText(t *testing.T) {
 		invalidData.Data.BaziInfo.DayCs = []string{"病", "绝", "冠带", "死"}
 		invalidData.Data.BaziInfo.NaYin = []string{"城头土", "涧下水", "天上火", "炉中火"}
 
-		result := service.formatDetailedText(&invalidData)
+		result, _ := service.formatDetailedText(&invalidData)
 		if result == "" {
 			t.Error("格式化结果不应为空")
 		}
@@ -124,4 +130,433 @@ func TestFormatDetailedText(t *testing.T) {
 			t.Error("方法应能处理数组越界情况而不panic")
 		}
 	})
+
+	// 测试大运信息不一致
+	t.Run("大运信息不一致", func(t *testing.T) {
+		invalidData := *testData
+		invalidData.Data.DayunInfo.YearsInfo5 = invalidData.Data.DayunInfo.YearsInfo5[:3] // 流年缺项
+		_, err := service.formatDetailedText(&invalidData)
+		var dayunErr *bazi.DayunError
+		if !errors.As(err, &dayunErr) || dayunErr.Field != "years_info5" || !errors.Is(err, bazi.ErrInconsistentDayun) {
+			t.Errorf("流年缺项应返回 *bazi.DayunError, got %v", err)
+		}
+	})
+}
+
+func TestNormalizeLunarDate(t *testing.T) {
+	service := &BaziAppService{}
+
+	t.Run("闰月转换为公历", func(t *testing.T) {
+		req := bazi.Request{Type: 0, Year: 2023, Month: 2, Day: 1, Leap: true}
+		note, _, hasError := service.normalizeLunarDate(&req)
+		if hasError {
+			t.Fatal("闰二月初一应转换成功")
+		}
+		if req.Type != 1 || req.Year != 2023 || req.Month != 3 || req.Day != 22 || req.Leap {
+			t.Errorf("转换结果 = %+v, want 公历2023年3月22日", req)
+		}
+		if !strings.Contains(note, "2023年闰二月初一") {
+			t.Errorf("转换说明缺少原始农历日期: %s", note)
+		}
+	})
+
+	t.Run("不存在的闰月", func(t *testing.T) {
+		req := bazi.Request{Type: 0, Year: 2023, Month: 3, Day: 1, Leap: true}
+		_, errMsg, hasError := service.normalizeLunarDate(&req)
+		if !hasError || !strings.Contains(errMsg, "闰二月") {
+			t.Errorf("应提示当年闰月为闰二月, got %s", errMsg)
+		}
+	})
+
+	t.Run("公历输入保持不变", func(t *testing.T) {
+		req := bazi.Request{Type: 1, Year: 2000, Month: 1, Day: 2, Leap: true}
+		note, _, hasError := service.normalizeLunarDate(&req)
+		if hasError || note != "" || req.Year != 2000 || req.Month != 1 || req.Day != 2 || req.Leap {
+			t.Errorf("公历输入不应被转换: %+v", req)
+		}
+	})
+}
+
+func TestApplyTrueSolarTime(t *testing.T) {
+	gazetteer, err := location.NewGazetteer([]location.Division{
+		{
+			Name: "新疆维吾尔自治区", Code: "650000", Seat: "乌鲁木齐",
+			Places: []location.Place{
+				{City: "乌鲁木齐", Longitude: 87.62, Latitude: 43.83},
+				{City: "伊犁", Approximate: true},
+			},
+		},
+		{
+			Name: "北京市", Code: "110000", Seat: "北京",
+			Places: []location.Place{{City: "北京", Longitude: 116.41, Latitude: 39.90}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewGazetteer() error = %v", err)
+	}
+	service := &BaziAppService{Gazetteer: gazetteer}
+
+	t.Run("跨越时辰边界", func(t *testing.T) {
+		// 乌鲁木齐 2024年2月11日12时，真太阳时约为9时36分，由午时变为巳时
+		req := bazi.Request{Type: 1, Year: 2024, Month: 2, Day: 11, Hours: 12, Zhen: 1, Province: "新疆维吾尔自治区", City: "乌鲁木齐"}
+		note := service.applyTrueSolarTime(&req)
+		if req.Zhen != 2 || req.Hours != 9 {
+			t.Errorf("校正后请求 = %+v, want zhen=2 hours=9", req)
+		}
+		if !strings.Contains(note, "午时 → 巳时") {
+			t.Errorf("应提示时辰变化:\n%s", note)
+		}
+	})
+
+	t.Run("以省会近似", func(t *testing.T) {
+		req := bazi.Request{Type: 1, Year: 2024, Month: 2, Day: 11, Hours: 12, Zhen: 1, Province: "新疆维吾尔自治区", City: "伊犁"}
+		if note := service.applyTrueSolarTime(&req); !strings.Contains(note, "按省会乌鲁木齐近似") {
+			t.Errorf("应提示按省会坐标近似:\n%s", note)
+		}
+	})
+
+	t.Run("不考虑真太阳时", func(t *testing.T) {
+		req := bazi.Request{Type: 1, Year: 2024, Month: 2, Day: 11, Hours: 12, Zhen: 2, Province: "北京市", City: "北京"}
+		if note := service.applyTrueSolarTime(&req); note != "" || req.Hours != 12 {
+			t.Errorf("zhen=2 时不应校正: %+v", req)
+		}
+	})
+}
+
+func TestValidateInput(t *testing.T) {
+	gazetteer, err := location.NewGazetteer([]location.Division{
+		{
+			Name: "北京市", Code: "110000", Seat: "北京",
+			Places: []location.Place{
+				{City: "北京", Longitude: 116.41, Latitude: 39.90},
+				{City: "通县", Longitude: 116.65, Latitude: 39.92},
+			},
+			Aliases: []location.Alias{
+				{Name: "通州", City: "通县", Kind: location.AliasModern},
+				{Name: "海淀", City: "北京", Kind: location.AliasDistrict},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewGazetteer() error = %v", err)
+	}
+	service := &BaziAppService{Gazetteer: gazetteer}
+
+	t.Run("今名解析为规范地名", func(t *testing.T) {
+		req := bazi.Request{Province: "北京", City: "通州区"}
+		note, _, hasError := service.validateInput(&req)
+		if hasError || req.Province != "北京市" || req.City != "通县" {
+			t.Fatalf("validateInput() = %+v", req)
+		}
+		if !strings.Contains(note, "【出生地匹配】") || !strings.Contains(note, "今名") {
+			t.Errorf("应说明匹配原因:\n%s", note)
+		}
+	})
+
+	t.Run("未填写省份", func(t *testing.T) {
+		req := bazi.Request{City: "海淀"}
+		if _, _, hasError := service.validateInput(&req); hasError || req.Province != "北京市" || req.City != "北京" {
+			t.Errorf("validateInput() = %+v", req)
+		}
+	})
+
+	t.Run("规范地名无需说明", func(t *testing.T) {
+		req := bazi.Request{Province: "北京市", City: "北京"}
+		if note, _, hasError := service.validateInput(&req); hasError || note != "" || req.Name != "求测者" {
+			t.Errorf("validateInput() = %+v, note = %q", req, note)
+		}
+	})
+
+	t.Run("无效城市", func(t *testing.T) {
+		req := bazi.Request{Province: "北京市", City: "乌鲁木齐"}
+		if _, errMsg, hasError := service.validateInput(&req); !hasError || !strings.Contains(errMsg, "无效城市") {
+			t.Errorf("应提示无效城市, got %q", errMsg)
+		}
+	})
+
+	t.Run("配置的默认值", func(t *testing.T) {
+		service := &BaziAppService{Gazetteer: gazetteer, Defaults: bazi.RequestDefaults{Sect: 2, Zhen: 2, Lang: "zh-tw"}}
+		req := bazi.Request{Sect: 1}
+		if _, _, hasError := service.validateInput(&req); hasError || req.Sect != 1 || req.Zhen != 2 || req.Lang != "zh-tw" {
+			t.Errorf("未填写的字段应使用默认值，已填写的保持不变: %+v", req)
+		}
+	})
+}
+
+func TestOverseasBirthplace(t *testing.T) {
+	gazetteer, err := location.NewGazetteer([]location.Division{
+		{
+			Name: "北京市", Code: "110000", Seat: "北京",
+			Places: []location.Place{{City: "北京", Longitude: 116.41, Latitude: 39.90}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewGazetteer() error = %v", err)
+	}
+	world, err := location.NewWorldGazetteer([]location.WorldCity{
+		{Name: "纽约", EnglishName: "New York", Country: "美国", Longitude: -74.01, Latitude: 40.71, TimeZone: "America/New_York"},
+	})
+	if err != nil {
+		t.Fatalf("NewWorldGazetteer() error = %v", err)
+	}
+	service := NewBaziAppService(nil, gazetteer, world)
+
+	t.Run("境外城市名称", func(t *testing.T) {
+		req := bazi.Request{City: "new york"}
+		note, _, hasError := service.validateInput(&req)
+		if hasError || req.City != "纽约" || req.Province != "" || !strings.Contains(note, "America/New_York") {
+			t.Errorf("validateInput() = %+v, note = %q", req, note)
+		}
+	})
+
+	t.Run("当地时间换算为北京时间", func(t *testing.T) {
+		// 纽约夏令时（UTC-4）2024年7月1日8时即北京时间当日20时
+		req := bazi.Request{Type: 1, Year: 2024, Month: 7, Day: 1, Hours: 8, Zhen: 2, City: "纽约"}
+		notes, _, hasError := service.prepareChartRequest(&req)
+		if hasError || req.Day != 1 || req.Hours != 20 || req.Minute != 0 {
+			t.Fatalf("换算结果 = %+v", req)
+		}
+		if len(notes) != 1 || !strings.Contains(notes[0], "【时区换算】") || !strings.Contains(notes[0], "UTC-4") {
+			t.Errorf("应说明时区换算: %v", notes)
+		}
+	})
+
+	t.Run("境外真太阳时", func(t *testing.T) {
+		// 纽约 2024年2月11日12时（UTC-5），经度时差约+3分58秒，均时差约-14分12秒
+		req := bazi.Request{Type: 1, Year: 2024, Month: 2, Day: 11, Hours: 12, Zhen: 1, City: "纽约"}
+		note := service.applyTrueSolarTime(&req)
+		if req.Zhen != 2 || req.Hours != 11 || req.Minute != 49 {
+			t.Errorf("校正后请求 = %+v, want 11时49分", req)
+		}
+		if !strings.Contains(note, "西经75°") || !strings.Contains(note, "西经74.01°") {
+			t.Errorf("应注明中央经线与出生地经度:\n%s", note)
+		}
+	})
+
+	t.Run("显式经纬度与时区", func(t *testing.T) {
+		// 悉尼夏令时（UTC+11）2024年1月15日10时即北京时间当日7时
+		lon, lat := 151.21, -33.87
+		req := bazi.Request{Type: 1, Year: 2024, Month: 1, Day: 15, Hours: 10, Zhen: 2, City: "Parramatta", Longitude: &lon, Latitude: &lat, Timezone: "Australia/Sydney"}
+		if _, errMsg, hasError := service.validateInput(&req); hasError {
+			t.Fatalf("validateInput() error = %s", errMsg)
+		}
+		if note := service.convertTimeZone(&req); req.Hours != 7 || !strings.Contains(note, "Parramatta") {
+			t.Errorf("换算结果 = %+v\n%s", req, note)
+		}
+	})
+
+	t.Run("无效坐标与时区", func(t *testing.T) {
+		lon, lat := 151.21, -33.87
+		tests := []bazi.Request{
+			{Latitude: &lat},
+			{Longitude: &lon, Latitude: &lat, Timezone: "Mars/Olympus"},
+			{Timezone: "Asia/Tokyo"},
+		}
+		for _, req := range tests {
+			if _, _, hasError := service.validateInput(&req); !hasError {
+				t.Errorf("validateInput(%+v) 应返回错误", req)
+			}
+		}
+	})
+}
+
+func TestAdjustHistoricalClock(t *testing.T) {
+	gazetteer, err := location.NewGazetteer([]location.Division{
+		{
+			Name: "北京市", Code: "110000", Seat: "北京",
+			Places: []location.Place{{City: "北京", Longitude: 116.41, Latitude: 39.90}},
+		},
+		{
+			Name: "四川省", Code: "510000", Seat: "成都",
+			Places: []location.Place{{City: "成都", Longitude: 104.07, Latitude: 30.67}},
+		},
+		{
+			Name: "港澳台", Code: "810000", Seat: "香港",
+			Places: []location.Place{{City: "香港", Longitude: 114.17, Latitude: 22.32}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewGazetteer() error = %v", err)
+	}
+	service := &BaziAppService{Gazetteer: gazetteer}
+
+	t.Run("夏令时", func(t *testing.T) {
+		req := bazi.Request{Type: 1, Year: 1988, Month: 7, Day: 1, Hours: 0, Minute: 30, Province: "北京市", City: "北京"}
+		note := service.adjustHistoricalClock(&req)
+		if req.Year != 1988 || req.Month != 6 || req.Day != 30 || req.Hours != 23 || req.Minute != 30 {
+			t.Errorf("校正后请求 = %+v, want 1988年6月30日23时30分", req)
+		}
+		if !strings.Contains(note, "【历史时制校正】") || !strings.Contains(note, "1988年4月17日2时至9月11日2时") {
+			t.Errorf("应注明夏令时区间:\n%s", note)
+		}
+	})
+
+	t.Run("农历输入保持农历", func(t *testing.T) {
+		// 农历1988年五月十七即公历1988年7月1日
+		req := bazi.Request{Type: 0, Year: 1988, Month: 5, Day: 17, Hours: 0, Minute: 30}
+		service.adjustHistoricalClock(&req)
+		if req.Type != 0 || req.Month != 5 || req.Day != 16 || req.Hours != 23 {
+			t.Errorf("校正后请求 = %+v, want 农历五月十六23时", req)
+		}
+	})
+
+	t.Run("夏令时切换时刻", func(t *testing.T) {
+		req := bazi.Request{Type: 1, Year: 1988, Month: 9, Day: 11, Hours: 1, Minute: 30}
+		if note := service.adjustHistoricalClock(&req); req.Hours != 0 || !strings.Contains(note, "出现两次") {
+			t.Errorf("应按夏令时处理并提示:\n%s", note)
+		}
+	})
+
+	t.Run("民国分区时制", func(t *testing.T) {
+		req := bazi.Request{Type: 1, Year: 1940, Month: 6, Day: 1, Hours: 12, Province: "四川省", City: "成都"}
+		note := service.adjustHistoricalClock(&req)
+		if req.Hours != 13 || !strings.Contains(note, "陇蜀时区") || !strings.Contains(note, "加1小时") {
+			t.Errorf("校正后请求 = %+v\n%s", req, note)
+		}
+	})
+
+	t.Run("无需校正", func(t *testing.T) {
+		lon, lat := 114.17, 22.32
+		tests := []bazi.Request{
+			{Type: 1, Year: 1992, Month: 7, Day: 1, Hours: 12, Province: "北京市", City: "北京"},
+			{Type: 1, Year: 1940, Month: 6, Day: 1, Hours: 12, Province: "北京市", City: "北京"},
+			{Type: 1, Year: 1988, Month: 7, Day: 1, Hours: 12, Province: "港澳台", City: "香港"},
+			{Type: 1, Year: 1988, Month: 7, Day: 1, Hours: 12, City: "纽约"},
+			{Type: 1, Year: 1988, Month: 7, Day: 1, Hours: 12, Longitude: &lon, Latitude: &lat, Timezone: "Asia/Hong_Kong"},
+		}
+		for _, req := range tests {
+			if note := service.adjustHistoricalClock(&req); note != "" || req.Hours != 12 {
+				t.Errorf("不应校正: %+v\n%s", req, note)
+			}
+		}
+	})
+}
+
+// stubService 返回固定排盘结果的领域服务
+type stubService struct {
+	resp *bazi.PaipanResponse
+	err  error
+	req  bazi.Request
+}
+
+func (s *stubService) GetPaipanResult(ctx context.Context, req bazi.Request) (*bazi.PaipanResponse, error) {
+	s.req = req
+	return s.resp, s.err
+}
+
+func TestGetPrompt(t *testing.T) {
+	stub := &stubService{resp: loadTestData(t)}
+	service := NewBaziAppService(stub, nil, nil)
+
+	result, err := service.GetPrompt(context.Background(), "bazi_career", map[string]string{"birth_time": "1999-12-20 04:00", "sex": "男", "name": "张三"})
+	if err != nil {
+		t.Fatalf("GetPrompt() error = %v", err)
+	}
+	if stub.req.Year != 1999 || stub.req.Month != 12 || stub.req.Day != 20 || stub.req.Hours != 4 {
+		t.Errorf("排盘请求 = %+v", stub.req)
+	}
+	if len(result.Messages) != 3 || !strings.Contains(result.Description, "事业财运") {
+		t.Errorf("GetPrompt() = %+v", result)
+	}
+
+	if _, err := service.GetPrompt(context.Background(), "bazi_career", map[string]string{"birth_time": "1999-12-20", "sex": "男"}); !errors.Is(err, bazi.ErrInvalidPromptArgument) {
+		t.Errorf("无效参数应返回 ErrInvalidPromptArgument, got %v", err)
+	}
+	if _, err := service.GetPrompt(context.Background(), "bazi_unknown", nil); err == nil {
+		t.Error("未知提示词应返回错误")
+	}
+
+	// 提示词未指定的流派、真太阳时与语言使用服务端配置的默认值
+	service.Defaults = bazi.RequestDefaults{Sect: 2, Zhen: 1, Lang: "zh-tw"}
+	if _, err := service.GetPrompt(context.Background(), "bazi_career", map[string]string{"birth_time": "1999-12-20 04:00", "sex": "男"}); err != nil {
+		t.Fatalf("GetPrompt() error = %v", err)
+	}
+	if stub.req.Sect != 2 || stub.req.Zhen != 1 || stub.req.Lang != "zh-tw" {
+		t.Errorf("排盘请求应使用配置的默认值: %+v", stub.req)
+	}
+}
+
+func TestPaipanChart(t *testing.T) {
+	resp := loadTestData(t)
+	resp.Provider = "local"
+	service := NewBaziAppService(&stubService{resp: resp}, nil, nil)
+	req := bazi.Request{Name: "张三", Sex: 0, Type: 0, Year: 1999, Month: 11, Day: 26, Hours: 3, Minute: 4, Output: bazi.OutputBoth}
+
+	result, err := service.Paipan(context.Background(), req)
+	if err != nil || result.IsError {
+		t.Fatalf("Paipan() = %+v, %v", result, err)
+	}
+	if result.Chart == nil || !strings.Contains(result.Text, "【八字排盘】") {
+		t.Fatalf("Paipan() 应同时返回文本与结构化结果: %+v", result)
+	}
+
+	text, err := result.JSON()
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	var chart bazi.Chart
+	if err := json.Unmarshal([]byte(text), &chart); err != nil {
+		t.Fatalf("JSON() 无法解析: %v", err)
+	}
+	if chart.Pillars.Day.Ganzhi != "己未" || chart.Pillars.Day.TenGod != "日元" || len(chart.Pillars.Hour.HiddenStems) != 3 {
+		t.Errorf("日柱、时柱 = %+v, %+v", chart.Pillars.Day, chart.Pillars.Hour)
+	}
+	if len(chart.Dayun) != 12 || len(chart.Dayun[0].Years) != 10 {
+		t.Fatalf("大运 = %+v", chart.Dayun)
+	}
+	if first := chart.Dayun[0].Years[0]; first != (bazi.LiuNian{Year: 2007, Age: 8, Ganzhi: "丁亥"}) {
+		t.Errorf("第一个流年 = %+v", first)
+	}
+	if len(chart.Notes) == 0 || !strings.HasPrefix(chart.Notes[0], "【历法转换】") {
+		t.Errorf("换算说明 = %q", chart.Notes)
+	}
+	if chart.Provider != "local" || !strings.Contains(result.Text, "本次排盘由 local 提供") {
+		t.Errorf("排盘提供方 = %q", chart.Provider)
+	}
+
+	req.Output = "xml"
+	if result, _ := service.Paipan(context.Background(), req); !result.IsError || result.Chart != nil {
+		t.Errorf("无效输出格式应返回错误: %+v", result)
+	}
+}
+
+func TestChartSchemaJSON(t *testing.T) {
+	text, err := ChartSchemaJSON()
+	if err != nil {
+		t.Fatalf("ChartSchemaJSON() error = %v", err)
+	}
+	var schema struct {
+		Properties map[string]struct {
+			Items *struct {
+				Properties map[string]any `json:"properties"`
+			} `json:"items"`
+			Properties map[string]any `json:"properties"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(text), &schema); err != nil {
+		t.Fatalf("ChartSchemaJSON() 无法解析: %v", err)
+	}
+	if _, ok := schema.Properties["pillars"].Properties["day"]; !ok {
+		t.Error("JSON Schema 缺少 pillars.day")
+	}
+	if dayun := schema.Properties["dayun"]; dayun.Items == nil || dayun.Items.Properties["years"] == nil {
+		t.Error("JSON Schema 缺少 dayun[].years")
+	}
+}
+
+func TestPaipanProviderError(t *testing.T) {
+	req := bazi.Request{Name: "张三", Sex: 0, Type: 1, Year: 2000, Month: 1, Day: 2, Hours: 3}
+
+	business := &stubService{err: &bazi.ProviderError{Kind: bazi.ErrorBusiness, Provider: "test", ErrCode: 10003, Message: "出生年份超出范围"}}
+	result, err := NewBaziAppService(business, nil, nil).Paipan(context.Background(), req)
+	if err != nil || !result.IsError || !strings.Contains(result.Text, "错误码：10003") || !strings.Contains(result.Text, "出生年份超出范围") {
+		t.Errorf("业务错误应作为输入错误提示: %+v, %v", result, err)
+	}
+
+	network := &stubService{err: &bazi.ProviderError{Kind: bazi.ErrorNetwork, Provider: "test", Err: context.DeadlineExceeded}}
+	_, err = NewBaziAppService(network, nil, nil).Paipan(context.Background(), req)
+	var providerErr *bazi.ProviderError
+	if !errors.As(err, &providerErr) || providerErr.Kind != bazi.ErrorNetwork || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("网络错误应原样返回: %v", err)
+	}
 }
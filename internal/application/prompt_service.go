@@ -0,0 +1,40 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// ErrPromptChart 提示词所需的排盘未能完成，错误信息中附带面向用户的提示
+var ErrPromptChart = errors.New("排盘失败")
+
+// GetPrompt 解析提示词参数并排盘，返回嵌入排盘结果的解读提示词。
+// 流年解读未指定年份时取当前年份。
+func (s *BaziAppService) GetPrompt(ctx context.Context, name string, args map[string]string) (*protocol.GetPromptResult, error) {
+	template, ok := bazi.FindPromptTemplate(name)
+	if !ok {
+		return nil, fmt.Errorf("未知提示词: %s", name)
+	}
+	input, err := bazi.ParsePromptArguments(args)
+	if err != nil {
+		return nil, err
+	}
+	if template.Annual && input.TargetYear == 0 {
+		input.TargetYear = time.Now().In(beijing).Year()
+	}
+
+	chartText, isAppError, err := s.GetBaziPaipan(ctx, input.Request)
+	if err != nil {
+		return nil, err
+	}
+	if isAppError {
+		return nil, fmt.Errorf("%w: %s", ErrPromptChart, chartText)
+	}
+	return bazi.GeneratePromptContent(template, input, chartText)
+}
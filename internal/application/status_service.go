@@ -0,0 +1,120 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// VersionInfo 版本信息
+type VersionInfo struct {
+	Version   string `json:"version"`            // 版本号
+	Revision  string `json:"revision,omitempty"` // 构建时的代码版本
+	GoVersion string `json:"go_version"`         // 构建使用的 Go 版本
+}
+
+// CacheStatus 排盘缓存统计
+type CacheStatus struct {
+	Entries     int     `json:"entries"`                // 内存中的缓存条数
+	Capacity    int     `json:"capacity"`               // 内存缓存条数上限
+	DiskEntries *int    `json:"disk_entries,omitempty"` // 磁盘缓存文件数，未启用磁盘缓存时为空
+	Hits        int64   `json:"hits"`                   // 命中次数（内存与磁盘）
+	DiskHits    int64   `json:"disk_hits"`              // 其中磁盘命中次数
+	Misses      int64   `json:"misses"`                 // 未命中次数
+	HitRate     float64 `json:"hit_rate"`               // 命中率
+}
+
+// ServerStatus 服务器运行状态
+type ServerStatus struct {
+	Version   VersionInfo           `json:"version"`
+	Transport string                `json:"transport"`       // 传输方式
+	Uptime    string                `json:"uptime"`          // 运行时长
+	Providers []bazi.ProviderStatus `json:"providers"`       // 各排盘提供方的状态，按调用顺序排列
+	Cache     *CacheStatus          `json:"cache,omitempty"` // 排盘缓存统计，未启用缓存时为空
+}
+
+// ProviderStatusReporter 报告各排盘提供方的运行状态
+type ProviderStatusReporter interface {
+	Status(ctx context.Context) []bazi.ProviderStatus
+}
+
+// StatusAppService 定义了服务器状态查询的应用服务。
+type StatusAppService struct {
+	Version    VersionInfo
+	Transport  string
+	StartedAt  time.Time
+	Providers  ProviderStatusReporter // 排盘提供方，为空时不报告
+	CacheStats func() CacheStatus     // 缓存统计，为空表示未启用缓存
+}
+
+// Status 探测各排盘提供方并汇总服务器状态
+func (s *StatusAppService) Status(ctx context.Context) ServerStatus {
+	status := ServerStatus{
+		Version:   s.Version,
+		Transport: s.Transport,
+		Uptime:    time.Since(s.StartedAt).Truncate(time.Second).String(),
+		Providers: []bazi.ProviderStatus{},
+	}
+	if s.Providers != nil {
+		status.Providers = s.Providers.Status(ctx)
+	}
+	if s.CacheStats != nil {
+		cache := s.CacheStats()
+		if total := cache.Hits + cache.Misses; total > 0 {
+			cache.HitRate = float64(cache.Hits) / float64(total)
+		}
+		status.Cache = &cache
+	}
+	return status
+}
+
+// Text 返回服务器状态的摘要
+func (st ServerStatus) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "【版本】%s", st.Version.Version)
+	if st.Version.Revision != "" {
+		fmt.Fprintf(&b, "（%s）", st.Version.Revision)
+	}
+	fmt.Fprintf(&b, "，%s\n【运行】%s，已运行 %s\n", st.Version.GoVersion, st.Transport, st.Uptime)
+
+	b.WriteString("【排盘提供方】\n")
+	for _, p := range st.Providers {
+		state := "可用"
+		if !p.Reachable {
+			state = "不可用"
+		}
+		fmt.Fprintf(&b, "- %s：%s", p.Name, state)
+		if p.LatencyMS > 0 {
+			fmt.Fprintf(&b, "，%dms", p.LatencyMS)
+		}
+		if p.Breaker != "" && p.Breaker != "closed" {
+			fmt.Fprintf(&b, "，熔断器 %s", p.Breaker)
+		}
+		if p.Quota == bazi.QuotaUnsupported {
+			b.WriteString("，剩余调用次数不支持查询")
+		}
+		if p.Detail != "" {
+			fmt.Fprintf(&b, "（%s）", p.Detail)
+		}
+		b.WriteString("\n")
+	}
+
+	if c := st.Cache; c != nil {
+		fmt.Fprintf(&b, "【排盘缓存】内存 %d/%d 条", c.Entries, c.Capacity)
+		if c.DiskEntries != nil {
+			fmt.Fprintf(&b, "，磁盘 %d 条", *c.DiskEntries)
+		}
+		fmt.Fprintf(&b, "，命中 %d 次（磁盘 %d 次），未命中 %d 次，命中率 %.1f%%\n", c.Hits, c.DiskHits, c.Misses, c.HitRate*100)
+	} else {
+		b.WriteString("【排盘缓存】未启用\n")
+	}
+	return b.String()
+}
+
+// JSON 返回服务器状态的 JSON 文本
+func (st ServerStatus) JSON() (string, error) {
+	return marshalResource(st)
+}
@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// stubReporter 返回固定的提供方状态
+type stubReporter []bazi.ProviderStatus
+
+func (r stubReporter) Status(ctx context.Context) []bazi.ProviderStatus { return r }
+
+func TestStatus(t *testing.T) {
+	diskEntries := 3
+	service := &StatusAppService{
+		Version:   VersionInfo{Version: "v1.2.0", Revision: "0123456789ab", GoVersion: "go1.24"},
+		Transport: "stdio",
+		StartedAt: time.Now().Add(-90 * time.Second),
+		Providers: stubReporter{
+			{Name: "yuanfenju", Reachable: true, LatencyMS: 120, Breaker: "closed", Quota: bazi.QuotaUnsupported},
+			{Name: "local", Detail: "不支持健康检查"},
+		},
+		CacheStats: func() CacheStatus {
+			return CacheStatus{Entries: 2, Capacity: 100, DiskEntries: &diskEntries, Hits: 3, DiskHits: 1, Misses: 1}
+		},
+	}
+
+	status := service.Status(context.Background())
+	if status.Uptime != "1m30s" || status.Cache == nil || status.Cache.HitRate != 0.75 || len(status.Providers) != 2 {
+		t.Errorf("Status() = %+v", status)
+	}
+	text := status.Text()
+	for _, want := range []string{"v1.2.0（0123456789ab）", "yuanfenju：可用，120ms，剩余调用次数不支持查询", "local：不可用（不支持健康检查）", "内存 2/100 条，磁盘 3 条", "命中率 75.0%"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text() 应包含 %q:\n%s", want, text)
+		}
+	}
+
+	statusJSON, err := status.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(statusJSON), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if providers := decoded["providers"].([]any); providers[0].(map[string]any)["quota"] != bazi.QuotaUnsupported {
+		t.Errorf("JSON() = %s", statusJSON)
+	}
+
+	// 未启用缓存、未配置提供方
+	empty := (&StatusAppService{Version: VersionInfo{Version: "dev"}, StartedAt: time.Now()}).Status(context.Background())
+	if empty.Cache != nil || empty.Providers == nil || !strings.Contains(empty.Text(), "【排盘缓存】未启用") {
+		t.Errorf("Status() = %+v", empty)
+	}
+}
@@ -0,0 +1,157 @@
+package bazi
+
+import "strings"
+
+// 排盘结果的输出格式
+const (
+	OutputText = "text" // 排盘文本（默认）
+	OutputJSON = "json" // 结构化排盘结果 Chart 的 JSON
+	OutputBoth = "both" // 排盘文本与 JSON
+)
+
+// ValidOutput 判断输出格式是否有效，空值视为 text
+func ValidOutput(output string) bool {
+	switch output {
+	case "", OutputText, OutputJSON, OutputBoth:
+		return true
+	}
+	return false
+}
+
+// Chart 结构化排盘结果，由提供方返回的 Data 规范化而来：四柱为对象，大运为按时间排序的数组，流年嵌套在所属大运中。
+// 字段的 description 标签即 JSON Schema 中的说明。
+type Chart struct {
+	Name          string          `json:"name" description:"姓名"`
+	Sex           string          `json:"sex" description:"乾造（男）或坤造（女）"`
+	Solar         string          `json:"solar" description:"公历出生时间（排盘所用的北京时间）"`
+	Lunar         string          `json:"lunar" description:"农历出生时间"`
+	Pattern       string          `json:"pattern" description:"提供方给出的八字正格"`
+	PatternDetail *PatternResult  `json:"pattern_detail,omitempty" description:"本地判定的格局：月令正格及化气格、专旺格、从格的规则、依据与可信度，可与 pattern 对照"`
+	Kongwang      string          `json:"kongwang" description:"日柱旬空"`
+	Zodiac        string          `json:"zodiac" description:"生肖"`
+	Constellation string          `json:"constellation" description:"星座"`
+	TrueSolarTime *TrueSolarTime  `json:"true_solar_time,omitempty" description:"提供方校正真太阳时的信息，本地校正时见 notes"`
+	Pillars       Pillars         `json:"pillars" description:"四柱"`
+	Qiyun         string          `json:"qiyun" description:"起运岁数，例：8年4月26天起运"`
+	Jiaoyun       string          `json:"jiaoyun" description:"交运时间"`
+	Dayun         []DayunPeriod   `json:"dayun" description:"大运，按时间排序"`
+	Elements      *ElementBalance `json:"elements,omitempty" description:"原局五行分布：天干各计 1，地支按藏干加权共计 1，并按月令旺衰调整"`
+	Relations     []Relation      `json:"relations,omitempty" description:"原局四柱之间的天干五合、相冲与地支六合、三合、三会、半合、六冲、三刑、自刑、六害、六破"`
+	Notes         []string        `json:"notes,omitempty" description:"出生地匹配、历法与时间换算等说明"`
+	Provider      string          `json:"provider,omitempty" description:"给出本次排盘结果的提供方，例：yuanfenju、local"`
+}
+
+// TrueSolarTime 提供方返回的真太阳时信息
+type TrueSolarTime struct {
+	Province  string `json:"province" description:"省份"`
+	City      string `json:"city" description:"城市"`
+	Longitude string `json:"longitude" description:"经度"`
+	Latitude  string `json:"latitude" description:"纬度"`
+	Offset    string `json:"offset" description:"真太阳时与北京时间的时差"`
+}
+
+// Pillars 四柱
+type Pillars struct {
+	Year  Pillar `json:"year" description:"年柱"`
+	Month Pillar `json:"month" description:"月柱"`
+	Day   Pillar `json:"day" description:"日柱"`
+	Hour  Pillar `json:"hour" description:"时柱"`
+}
+
+// Pillar 一柱的干支及其十神、藏干、长生、纳音、神煞
+type Pillar struct {
+	Ganzhi      string       `json:"ganzhi" description:"干支，例：己卯"`
+	Stem        string       `json:"stem" description:"天干"`
+	Branch      string       `json:"branch" description:"地支"`
+	TenGod      string       `json:"ten_god" description:"天干十神，日柱为“日元”"`
+	HiddenStems []HiddenStem `json:"hidden_stems" description:"地支藏干，按本气、中气、余气排序"`
+	Growth      string       `json:"growth" description:"日干在本柱地支的十二长生（星运）"`
+	SelfSeat    string       `json:"self_seat" description:"本柱天干坐地支的十二长生（自坐）"`
+	Nayin       string       `json:"nayin" description:"纳音"`
+	Kongwang    string       `json:"kongwang" description:"本柱所在旬的旬空"`
+	Shensha     []string     `json:"shensha" description:"神煞"`
+}
+
+// HiddenStem 地支藏干及其十神
+type HiddenStem struct {
+	Stem   string `json:"stem" description:"藏干"`
+	TenGod string `json:"ten_god" description:"藏干十神"`
+}
+
+// NewChart 将提供方返回的排盘数据规范化为 Chart，大运信息不一致时返回 *DayunError
+func NewChart(data Data) (Chart, error) {
+	base, info, detail := data.BaseInfo, data.BaziInfo, data.DetailInfo
+	dayun, err := NewDayunPeriods(data.DayunInfo, detail.Dayunshensha)
+	if err != nil {
+		return Chart{}, err
+	}
+	chart := Chart{
+		Name:          base.Name,
+		Sex:           base.Sex,
+		Solar:         base.Gongli,
+		Lunar:         base.Nongli,
+		Pattern:       base.Zhengge,
+		Kongwang:      info.Kw,
+		Zodiac:        data.StartInfo.Sx,
+		Constellation: data.StartInfo.Xz,
+		Qiyun:         base.Qiyun,
+		Jiaoyun:       base.Jiaoyun,
+		Pillars: Pillars{
+			Year: newPillar(detail.Sizhu.Year.Tg, detail.Sizhu.Year.Dz, detail.Zhuxing.Year, detail.Canggan.Year, detail.Fuxing.Year,
+				detail.Xingyun.Year, detail.Zizuo.Year, detail.Nayin.Year, detail.Kongwang.Year, detail.Shensha.Year),
+			Month: newPillar(detail.Sizhu.Month.Tg, detail.Sizhu.Month.Dz, detail.Zhuxing.Month, detail.Canggan.Month, detail.Fuxing.Month,
+				detail.Xingyun.Month, detail.Zizuo.Month, detail.Nayin.Month, detail.Kongwang.Month, detail.Shensha.Month),
+			Day: newPillar(detail.Sizhu.Day.Tg, detail.Sizhu.Day.Dz, detail.Zhuxing.Day, detail.Canggan.Day, detail.Fuxing.Day,
+				detail.Xingyun.Day, detail.Zizuo.Day, detail.Nayin.Day, detail.Kongwang.Day, detail.Shensha.Day),
+			Hour: newPillar(detail.Sizhu.Hour.Tg, detail.Sizhu.Hour.Dz, detail.Zhuxing.Hour, detail.Canggan.Hour, detail.Fuxing.Hour,
+				detail.Xingyun.Hour, detail.Zizuo.Hour, detail.Nayin.Hour, detail.Kongwang.Hour, detail.Shensha.Hour),
+		},
+		Dayun: dayun,
+	}
+	if elements, err := AnalyzeElements(info); err == nil {
+		chart.Elements = &elements
+	}
+	if pattern, err := ClassifyPattern(info); err == nil {
+		chart.PatternDetail = &pattern
+	}
+	if relations, err := NatalRelations(info); err == nil {
+		chart.Relations = relations
+	}
+	if zhen := base.Zhen; zhen != nil {
+		chart.TrueSolarTime = &TrueSolarTime{Province: zhen.Province, City: zhen.City, Longitude: zhen.Jingdu, Latitude: zhen.Weidu, Offset: zhen.Shicha}
+	}
+	return chart, nil
+}
+
+// newPillar 由详细信息中同一柱位的各项组成一柱
+func newPillar(stem, branch, tenGod string, canggan, fuxing []string, growth, selfSeat, nayin, kongwang, shensha string) Pillar {
+	p := Pillar{
+		Ganzhi:      stem + branch,
+		Stem:        stem,
+		Branch:      branch,
+		TenGod:      tenGod,
+		HiddenStems: make([]HiddenStem, 0, len(canggan)),
+		Growth:      growth,
+		SelfSeat:    selfSeat,
+		Nayin:       nayin,
+		Kongwang:    kongwang,
+		Shensha:     splitShensha(shensha),
+	}
+	for i, s := range canggan {
+		h := HiddenStem{Stem: s}
+		if i < len(fuxing) {
+			h.TenGod = fuxing[i]
+		}
+		p.HiddenStems = append(p.HiddenStems, h)
+	}
+	return p
+}
+
+// splitShensha 将以空格分隔的神煞拆分为数组
+func splitShensha(s string) []string {
+	names := strings.Fields(s)
+	if names == nil {
+		return []string{}
+	}
+	return names
+}
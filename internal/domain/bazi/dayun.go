@@ -0,0 +1,141 @@
+package bazi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInconsistentDayun 提供方返回的大运信息不一致
+var ErrInconsistentDayun = errors.New("大运信息不一致")
+
+// DayunError 描述大运信息中不一致的字段，errors.Is 可匹配 ErrInconsistentDayun
+type DayunError struct {
+	Period int    // 第几步大运，从 0 起；-1 表示整个字段
+	Field  string // 提供方数据中的字段名，如 big_god、years_info3
+	Reason string // 不一致的原因
+}
+
+// Error 返回错误描述
+func (e *DayunError) Error() string {
+	if e.Period < 0 {
+		return fmt.Sprintf("%v: %s %s", ErrInconsistentDayun, e.Field, e.Reason)
+	}
+	return fmt.Sprintf("%v: 第%d步大运 %s %s", ErrInconsistentDayun, e.Period+1, e.Field, e.Reason)
+}
+
+// Unwrap 返回 ErrInconsistentDayun
+func (e *DayunError) Unwrap() error {
+	return ErrInconsistentDayun
+}
+
+// DayunPeriod 一步大运及其流年
+type DayunPeriod struct {
+	Ganzhi    string    `json:"ganzhi" description:"大运干支"`
+	TenGod    string    `json:"ten_god" description:"大运天干十神"`
+	Growth    string    `json:"growth" description:"日干在大运地支的十二长生"`
+	StartAge  int       `json:"start_age" description:"起运虚岁"`
+	StartYear int       `json:"start_year" description:"起运年份"`
+	EndYear   int       `json:"end_year" description:"止运年份"`
+	Shensha   []string  `json:"shensha" description:"大运神煞"`
+	Years     []LiuNian `json:"years" description:"流年，按时间排序"`
+}
+
+// LiuNian 流年
+type LiuNian struct {
+	Year   int    `json:"year" description:"公历年份"`
+	Age    int    `json:"age" description:"虚岁"`
+	Ganzhi string `json:"ganzhi" description:"流年干支"`
+}
+
+// YearsInfo 返回十个流年字段，第 k 个字段的第 i 项为第 i 步大运的第 k 个流年
+func (d *DayunInfo) YearsInfo() [][]YearChar {
+	return [][]YearChar{
+		d.YearsInfo0, d.YearsInfo1, d.YearsInfo2, d.YearsInfo3, d.YearsInfo4,
+		d.YearsInfo5, d.YearsInfo6, d.YearsInfo7, d.YearsInfo8, d.YearsInfo9,
+	}
+}
+
+// NewDayunPeriods 将提供方按字段平铺的大运信息转换为大运数组，并校验各字段的一致性：
+// 各字段长度须与大运数相同，干支须有效，流年须逐年相连且与起运年份相符。
+// shensha 为按时间排序的大运神煞，干支与大运不符的项忽略。信息不一致时返回 *DayunError。
+func NewDayunPeriods(info DayunInfo, shensha []DayunShensha) ([]DayunPeriod, error) {
+	count := len(info.Big)
+	yearsInfo := info.YearsInfo()
+	type field struct {
+		name   string
+		length int
+	}
+	fields := []field{
+		{"big_god", len(info.BigGod)},
+		{"big_cs", len(info.BigCs)},
+		{"xu_sui", len(info.XuSui)},
+		{"big_start_year", len(info.BigStartYear)},
+		{"big_end_year", len(info.BigEndYear)},
+	}
+	for k, years := range yearsInfo {
+		fields = append(fields, field{fmt.Sprintf("years_info%d", k), len(years)})
+	}
+	for _, f := range fields {
+		if f.length != count {
+			return nil, &DayunError{Period: -1, Field: f.name, Reason: fmt.Sprintf("共 %d 项，与大运数 %d 不符", f.length, count)}
+		}
+	}
+
+	periods := make([]DayunPeriod, 0, count)
+	for i, ganzhi := range info.Big {
+		if _, _, ok := ParseGanZhi(ganzhi); !ok {
+			return nil, &DayunError{Period: i, Field: "big", Reason: fmt.Sprintf("无效干支 %q", ganzhi)}
+		}
+		if info.BigEndYear[i] < info.BigStartYear[i] {
+			return nil, &DayunError{Period: i, Field: "big_end_year", Reason: fmt.Sprintf("止运年份 %d 早于起运年份 %d", info.BigEndYear[i], info.BigStartYear[i])}
+		}
+		p := DayunPeriod{
+			Ganzhi:    ganzhi,
+			TenGod:    info.BigGod[i],
+			Growth:    info.BigCs[i],
+			StartAge:  info.XuSui[i],
+			StartYear: info.BigStartYear[i],
+			EndYear:   info.BigEndYear[i],
+			Shensha:   []string{},
+		}
+		if i < len(shensha) && shensha[i].Tgdz == ganzhi {
+			p.Shensha = splitShensha(shensha[i].Shensha)
+		}
+
+		ganzhis := make([]string, 0, len(yearsInfo))
+		for _, years := range yearsInfo {
+			ganzhis = append(ganzhis, years[i].YearChar)
+		}
+		years, err := newLiuNian(i, p, ganzhis)
+		if err != nil {
+			return nil, err
+		}
+		p.Years = years
+		periods = append(periods, p)
+	}
+	return periods, nil
+}
+
+// newLiuNian 按流年干支推算公历年份与虚岁：首个流年取起运年份前后五年内干支相符的年份，其后须逐年相连
+func newLiuNian(period int, p DayunPeriod, ganzhis []string) ([]LiuNian, error) {
+	first, found := 0, false
+	for year := p.StartYear - 5; year <= p.StartYear+5; year++ {
+		if YearGanZhi(year) == ganzhis[0] {
+			first, found = year, true
+			break
+		}
+	}
+	if !found {
+		return nil, &DayunError{Period: period, Field: "years_info0", Reason: fmt.Sprintf("流年 %q 与起运年份 %d 不符", ganzhis[0], p.StartYear)}
+	}
+
+	years := make([]LiuNian, 0, len(ganzhis))
+	for k, ganzhi := range ganzhis {
+		year := first + k
+		if want := YearGanZhi(year); ganzhi != want {
+			return nil, &DayunError{Period: period, Field: fmt.Sprintf("years_info%d", k), Reason: fmt.Sprintf("流年 %q 与上一流年不相连，应为 %d 年的 %s", ganzhi, year, want)}
+		}
+		years = append(years, LiuNian{Year: year, Age: p.StartAge + year - p.StartYear, Ganzhi: ganzhi})
+	}
+	return years, nil
+}
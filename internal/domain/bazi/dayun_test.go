@@ -0,0 +1,83 @@
+package bazi
+
+import (
+	"errors"
+	"testing"
+)
+
+// testDayunInfo 构造起运于 2008 年的两步大运，流年自起运前一年起列出
+func testDayunInfo() DayunInfo {
+	info := DayunInfo{
+		BigGod:       []string{"七杀", "正官"},
+		Big:          []string{"乙亥", "甲戌"},
+		BigCs:        []string{"胎", "养"},
+		XuSui:        []int{9, 19},
+		BigStartYear: []int{2008, 2018},
+		BigEndYear:   []int{2017, 2027},
+	}
+	fields := []*[]YearChar{
+		&info.YearsInfo0, &info.YearsInfo1, &info.YearsInfo2, &info.YearsInfo3, &info.YearsInfo4,
+		&info.YearsInfo5, &info.YearsInfo6, &info.YearsInfo7, &info.YearsInfo8, &info.YearsInfo9,
+	}
+	for _, start := range info.BigStartYear {
+		for k, field := range fields {
+			*field = append(*field, YearChar{YearChar: YearGanZhi(start + k - 1)})
+		}
+	}
+	return info
+}
+
+func TestNewDayunPeriods(t *testing.T) {
+	shensha := []DayunShensha{{Tgdz: "乙亥", Shensha: "天医 国印贵人 "}, {Tgdz: "癸酉", Shensha: "文昌贵人"}}
+	periods, err := NewDayunPeriods(testDayunInfo(), shensha)
+	if err != nil {
+		t.Fatalf("NewDayunPeriods() error = %v", err)
+	}
+	if len(periods) != 2 {
+		t.Fatalf("NewDayunPeriods() = %+v", periods)
+	}
+	first := periods[0]
+	if first.Ganzhi != "乙亥" || first.TenGod != "七杀" || first.Growth != "胎" || first.StartAge != 9 || first.StartYear != 2008 || first.EndYear != 2017 {
+		t.Errorf("第一步大运 = %+v", first)
+	}
+	if len(first.Years) != 10 || first.Years[0] != (LiuNian{Year: 2007, Age: 8, Ganzhi: "丁亥"}) || first.Years[9] != (LiuNian{Year: 2016, Age: 17, Ganzhi: "丙申"}) {
+		t.Errorf("第一步大运流年 = %+v", first.Years)
+	}
+	if len(first.Shensha) != 2 || len(periods[1].Shensha) != 0 {
+		t.Errorf("大运神煞 = %q, %q（干支不符的项应忽略）", first.Shensha, periods[1].Shensha)
+	}
+
+	if periods, err := NewDayunPeriods(DayunInfo{}, nil); err != nil || len(periods) != 0 {
+		t.Errorf("空大运信息 = %+v, %v", periods, err)
+	}
+}
+
+func TestNewDayunPeriodsInconsistent(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*DayunInfo)
+		period int
+		field  string
+	}{
+		{"十神缺项", func(d *DayunInfo) { d.BigGod = d.BigGod[:1] }, -1, "big_god"},
+		{"流年缺项", func(d *DayunInfo) { d.YearsInfo9 = d.YearsInfo9[:1] }, -1, "years_info9"},
+		{"无效大运干支", func(d *DayunInfo) { d.Big[1] = "甲丑" }, 1, "big"},
+		{"止运早于起运", func(d *DayunInfo) { d.BigEndYear[0] = 2000 }, 0, "big_end_year"},
+		{"流年与起运年份不符", func(d *DayunInfo) { d.BigStartYear[1], d.BigEndYear[1] = 2048, 2057 }, 1, "years_info0"},
+		{"流年不相连", func(d *DayunInfo) { d.YearsInfo4[0] = YearChar{YearChar: "甲子"} }, 0, "years_info4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := testDayunInfo()
+			tt.modify(&info)
+			_, err := NewDayunPeriods(info, nil)
+			var dayunErr *DayunError
+			if !errors.As(err, &dayunErr) || !errors.Is(err, ErrInconsistentDayun) {
+				t.Fatalf("NewDayunPeriods() error = %v, want *DayunError", err)
+			}
+			if dayunErr.Period != tt.period || dayunErr.Field != tt.field {
+				t.Errorf("DayunError = %+v, want period %d field %s", dayunErr, tt.period, tt.field)
+			}
+		})
+	}
+}
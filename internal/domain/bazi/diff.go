@@ -0,0 +1,90 @@
+package bazi
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Difference 两个排盘结果中不一致的一项
+type Difference struct {
+	Field string `json:"field"` // 字段路径，例：pillars.day.ten_god、dayun[2].start_year
+	Left  string `json:"left"`  // 左侧提供方的值，缺失时为空
+	Right string `json:"right"` // 右侧提供方的值，缺失时为空
+}
+
+// DiffCharts 比较两个结构化排盘结果的四柱（干支、十神、藏干、长生、纳音、空亡、神煞）与大运（干支、十神、起止年份、神煞、流年），
+// 按字段路径返回不一致之处，没有差异时返回空切片。神煞按集合比较，不计顺序；姓名、说明与提供方等描述性字段不参与比较。
+func DiffCharts(left, right Chart) []Difference {
+	d := &differ{diffs: []Difference{}}
+	d.compare("kongwang", left.Kongwang, right.Kongwang)
+	d.compare("zodiac", left.Zodiac, right.Zodiac)
+	d.comparePillar("pillars.year", left.Pillars.Year, right.Pillars.Year)
+	d.comparePillar("pillars.month", left.Pillars.Month, right.Pillars.Month)
+	d.comparePillar("pillars.day", left.Pillars.Day, right.Pillars.Day)
+	d.comparePillar("pillars.hour", left.Pillars.Hour, right.Pillars.Hour)
+
+	d.compare("dayun.length", strconv.Itoa(len(left.Dayun)), strconv.Itoa(len(right.Dayun)))
+	for i := 0; i < len(left.Dayun) && i < len(right.Dayun); i++ {
+		d.compareDayun(fmt.Sprintf("dayun[%d]", i), left.Dayun[i], right.Dayun[i])
+	}
+	return d.diffs
+}
+
+// differ 收集比较过程中发现的不一致
+type differ struct {
+	diffs []Difference
+}
+
+func (d *differ) compare(field, left, right string) {
+	if left != right {
+		d.diffs = append(d.diffs, Difference{Field: field, Left: left, Right: right})
+	}
+}
+
+func (d *differ) comparePillar(field string, left, right Pillar) {
+	d.compare(field+".ganzhi", left.Ganzhi, right.Ganzhi)
+	d.compare(field+".ten_god", left.TenGod, right.TenGod)
+	d.compare(field+".hidden_stems", joinHiddenStems(left.HiddenStems), joinHiddenStems(right.HiddenStems))
+	d.compare(field+".growth", left.Growth, right.Growth)
+	d.compare(field+".self_seat", left.SelfSeat, right.SelfSeat)
+	d.compare(field+".nayin", left.Nayin, right.Nayin)
+	d.compare(field+".kongwang", left.Kongwang, right.Kongwang)
+	d.compare(field+".shensha", joinSet(left.Shensha), joinSet(right.Shensha))
+}
+
+func (d *differ) compareDayun(field string, left, right DayunPeriod) {
+	d.compare(field+".ganzhi", left.Ganzhi, right.Ganzhi)
+	d.compare(field+".ten_god", left.TenGod, right.TenGod)
+	d.compare(field+".start_age", strconv.Itoa(left.StartAge), strconv.Itoa(right.StartAge))
+	d.compare(field+".start_year", strconv.Itoa(left.StartYear), strconv.Itoa(right.StartYear))
+	d.compare(field+".end_year", strconv.Itoa(left.EndYear), strconv.Itoa(right.EndYear))
+	d.compare(field+".shensha", joinSet(left.Shensha), joinSet(right.Shensha))
+	d.compare(field+".years", joinYears(left.Years), joinYears(right.Years))
+}
+
+// joinHiddenStems 将藏干及其十神拼接为可比较的文本，例：癸(正财) 辛(偏印)
+func joinHiddenStems(stems []HiddenStem) string {
+	parts := make([]string, 0, len(stems))
+	for _, h := range stems {
+		parts = append(parts, h.Stem+"("+h.TenGod+")")
+	}
+	return strings.Join(parts, " ")
+}
+
+// joinSet 将神煞排序后拼接，使比较与顺序无关
+func joinSet(names []string) string {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, " ")
+}
+
+// joinYears 将流年拼接为可比较的文本，例：2007丁亥 2008戊子
+func joinYears(years []LiuNian) string {
+	parts := make([]string, 0, len(years))
+	for _, y := range years {
+		parts = append(parts, strconv.Itoa(y.Year)+y.Ganzhi)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,56 @@
+package bazi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffCharts(t *testing.T) {
+	dayun, err := NewDayunPeriods(testDayunInfo(), []DayunShensha{{Tgdz: "乙亥", Shensha: "天医 国印贵人"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newChart := func() Chart {
+		periods := make([]DayunPeriod, len(dayun))
+		for i, p := range dayun {
+			p.Shensha = append([]string(nil), p.Shensha...)
+			periods[i] = p
+		}
+		return Chart{
+			Name:     "张三",
+			Kongwang: "子丑",
+			Pillars: Pillars{
+				Day: Pillar{Ganzhi: "己未", TenGod: "日元", HiddenStems: []HiddenStem{{Stem: "己", TenGod: "比肩"}}, Shensha: []string{"天乙贵人", "华盖"}},
+			},
+			Dayun: periods,
+		}
+	}
+
+	left, right := newChart(), newChart()
+	right.Name = "李四"
+	right.Provider = "local"
+	right.Pillars.Day.Shensha = []string{"华盖", "天乙贵人"}
+	right.Dayun[0].Shensha = []string{"国印贵人", "天医"}
+	if diffs := DiffCharts(left, right); len(diffs) != 0 {
+		t.Errorf("描述性字段与神煞顺序不应计为差异: %+v", diffs)
+	}
+
+	right.Pillars.Day.TenGod = "比肩"
+	right.Pillars.Day.Shensha = []string{"华盖"}
+	right.Dayun[1].StartYear = 2019
+	right.Dayun = right.Dayun[:2]
+	want := []Difference{
+		{Field: "pillars.day.ten_god", Left: "日元", Right: "比肩"},
+		{Field: "pillars.day.shensha", Left: "华盖 天乙贵人", Right: "华盖"},
+		{Field: "dayun[1].start_year", Left: "2018", Right: "2019"},
+	}
+	if diffs := DiffCharts(left, right); !reflect.DeepEqual(diffs, want) {
+		t.Errorf("DiffCharts() = %+v，期望 %+v", diffs, want)
+	}
+
+	right.Dayun = right.Dayun[:1]
+	diffs := DiffCharts(left, right)
+	if last := diffs[len(diffs)-1]; last != (Difference{Field: "dayun.length", Left: "2", Right: "1"}) {
+		t.Errorf("大运步数不同 = %+v", diffs)
+	}
+}
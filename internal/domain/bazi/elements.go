@@ -0,0 +1,76 @@
+package bazi
+
+// seasonFactors 月令旺相休囚死对五行力量的调整系数
+var seasonFactors = map[string]float64{"旺": 1.5, "相": 1.2, "休": 1, "囚": 0.8, "死": 0.6}
+
+// 五行偏旺、偏弱的占比界限（按月令调整后），八字共八个字，五行平均各占 20%
+const (
+	excessShare = 35
+	weakShare   = 10
+)
+
+// ElementBalance 原局五行分布：天干各计 1，地支按藏干本气、中气、余气加权共计 1
+type ElementBalance struct {
+	Elements []ElementCount `json:"elements" description:"五行分布，按木火土金水排序"`
+	Missing  []string       `json:"missing" description:"天干与藏干中均未出现的五行"`
+	Excess   []string       `json:"excess" description:"按月令调整后占比不低于 35% 的五行"`
+	Weak     []string       `json:"weak" description:"按月令调整后占比低于 10% 但未缺失的五行"`
+}
+
+// ElementCount 一种五行的个数
+type ElementCount struct {
+	Element  string  `json:"element" description:"五行"`
+	Stems    int     `json:"stems" description:"天干个数，含日主"`
+	Hidden   float64 `json:"hidden" description:"地支藏干按本气、中气、余气加权的个数"`
+	Total    float64 `json:"total" description:"天干与加权藏干合计"`
+	Season   string  `json:"season" description:"在月令的旺衰：旺、相、休、囚、死"`
+	Adjusted float64 `json:"adjusted" description:"按月令旺衰调整后的合计：旺 ×1.5、相 ×1.2、休 ×1、囚 ×0.8、死 ×0.6"`
+	Share    float64 `json:"share" description:"调整后占五行总量的百分比"`
+}
+
+// AnalyzeElements 统计原局五行分布，八字信息无效时返回 ErrInvalidBazi
+func AnalyzeElements(info BaziInfo) (ElementBalance, error) {
+	n, err := parseNatal(info)
+	if err != nil {
+		return ElementBalance{}, err
+	}
+	season := BranchWuXing(n.branches[MonthPillar])
+
+	counts := make([]ElementCount, 5)
+	for w := range counts {
+		counts[w].Element = WuXing(w).String()
+		counts[w].Season = Season(WuXing(w), season)
+	}
+	for p := range n.stems {
+		counts[StemWuXing(n.stems[p])].Stems++
+		for i, stem := range n.hidden[p] {
+			counts[StemWuXing(stem)].Hidden += hiddenWeight(len(n.hidden[p]), i)
+		}
+	}
+
+	var adjustedTotal float64
+	for w := range counts {
+		c := &counts[w]
+		c.Hidden = round2(c.Hidden)
+		c.Total = round2(float64(c.Stems) + c.Hidden)
+		c.Adjusted = round2(c.Total * seasonFactors[c.Season])
+		adjustedTotal += c.Adjusted
+	}
+
+	b := ElementBalance{Elements: counts, Missing: []string{}, Excess: []string{}, Weak: []string{}}
+	for w := range counts {
+		c := &counts[w]
+		if adjustedTotal > 0 {
+			c.Share = round1(c.Adjusted / adjustedTotal * 100)
+		}
+		switch {
+		case c.Total == 0:
+			b.Missing = append(b.Missing, c.Element)
+		case c.Share >= excessShare:
+			b.Excess = append(b.Excess, c.Element)
+		case c.Share < weakShare:
+			b.Weak = append(b.Weak, c.Element)
+		}
+	}
+	return b, nil
+}
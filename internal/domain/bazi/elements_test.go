@@ -0,0 +1,35 @@
+package bazi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeElements(t *testing.T) {
+	b, err := AnalyzeElements(testBaziInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ElementCount{
+		{Element: "木", Stems: 0, Hidden: 1.7, Total: 1.7, Season: "相", Adjusted: 2.04, Share: 28.1},
+		{Element: "火", Stems: 2, Hidden: 0.6, Total: 2.6, Season: "死", Adjusted: 1.56, Share: 21.5},
+		{Element: "土", Stems: 2, Hidden: 0.7, Total: 2.7, Season: "囚", Adjusted: 2.16, Share: 29.8},
+		{Element: "金", Stems: 0, Hidden: 0, Total: 0, Season: "休", Adjusted: 0, Share: 0},
+		{Element: "水", Stems: 0, Hidden: 1, Total: 1, Season: "旺", Adjusted: 1.5, Share: 20.7},
+	}
+	if !reflect.DeepEqual(b.Elements, want) {
+		t.Errorf("Elements =\n%+v\n期望\n%+v", b.Elements, want)
+	}
+	if !reflect.DeepEqual(b.Missing, []string{"金"}) || len(b.Excess) != 0 || len(b.Weak) != 0 {
+		t.Errorf("缺失 %q，偏旺 %q，偏弱 %q", b.Missing, b.Excess, b.Weak)
+	}
+
+	// 甲寅 丙寅 甲寅 甲子：木当令且天干三见
+	b, err = AnalyzeElements(BaziInfo{Bazi: []string{"甲寅", "丙寅", "甲寅", "甲子"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(b.Excess, []string{"木"}) || !reflect.DeepEqual(b.Weak, []string{"土", "水"}) || !reflect.DeepEqual(b.Missing, []string{"金"}) {
+		t.Errorf("缺失 %q，偏旺 %q，偏弱 %q: %+v", b.Missing, b.Excess, b.Weak, b.Elements)
+	}
+}
@@ -0,0 +1,75 @@
+package bazi
+
+import "fmt"
+
+// ErrorKind 排盘提供方调用失败的类别
+type ErrorKind int
+
+const (
+	ErrorNetwork     ErrorKind = iota + 1 // 网络错误或请求超时
+	ErrorHTTPStatus                       // HTTP 状态码不是 2xx
+	ErrorDecode                           // 响应内容无法解析
+	ErrorBusiness                         // 提供方返回非 0 的业务错误码（errcode）
+	ErrorCircuitOpen                      // 提供方连续失败已熔断，未发起请求
+)
+
+// String 返回错误类别的中文名称
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorNetwork:
+		return "网络错误"
+	case ErrorHTTPStatus:
+		return "HTTP 状态异常"
+	case ErrorDecode:
+		return "响应解析失败"
+	case ErrorBusiness:
+		return "业务错误"
+	case ErrorCircuitOpen:
+		return "服务熔断"
+	}
+	return "未知错误"
+}
+
+// ProviderError 排盘提供方调用失败的错误，按 Kind 区分失败类别
+type ProviderError struct {
+	Kind       ErrorKind
+	Provider   string // 提供方名称
+	StatusCode int    // HTTP 状态码，仅 ErrorHTTPStatus
+	ErrCode    int    // 业务错误码，仅 ErrorBusiness
+	Message    string // 错误说明，如业务错误信息或响应内容摘要
+	Err        error  // 底层错误
+}
+
+// Error 返回错误描述
+func (e *ProviderError) Error() string {
+	msg := fmt.Sprintf("%s %s", e.Provider, e.Kind)
+	switch e.Kind {
+	case ErrorHTTPStatus:
+		msg += fmt.Sprintf(" %d", e.StatusCode)
+	case ErrorBusiness:
+		msg += fmt.Sprintf(" %d", e.ErrCode)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap 返回底层错误
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// Temporary 判断错误是否为暂时性的，可以重试：网络错误、HTTP 429 与 5xx
+func (e *ProviderError) Temporary() bool {
+	switch e.Kind {
+	case ErrorNetwork:
+		return true
+	case ErrorHTTPStatus:
+		return e.StatusCode == 429 || e.StatusCode >= 500
+	}
+	return false
+}
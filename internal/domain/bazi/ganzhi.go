@@ -0,0 +1,198 @@
+package bazi
+
+// WuXing 表示五行值对象
+type WuXing int
+
+const (
+	Mu   WuXing = iota // 木
+	Huo                // 火
+	Tu                 // 土
+	Jin                // 金
+	Shui               // 水
+)
+
+var wuXingNames = []string{"木", "火", "土", "金", "水"}
+
+// String 返回五行的中文名称
+func (w WuXing) String() string {
+	if w < Mu || w > Shui {
+		return ""
+	}
+	return wuXingNames[w]
+}
+
+// Generates 返回本五行所生的五行（木生火、火生土……）
+func (w WuXing) Generates() WuXing {
+	return (w + 1) % 5
+}
+
+// Controls 返回本五行所克的五行（木克土、土克水……）
+func (w WuXing) Controls() WuXing {
+	return (w + 2) % 5
+}
+
+// GeneratedBy 返回生本五行的五行（火由木生、土由火生……）
+func (w WuXing) GeneratedBy() WuXing {
+	return (w + 4) % 5
+}
+
+// ControlledBy 返回克本五行的五行（土受木克、水受土克……）
+func (w WuXing) ControlledBy() WuXing {
+	return (w + 3) % 5
+}
+
+// TianGan 十天干（按甲乙丙丁戊己庚辛壬癸排序）
+var TianGan = []string{"甲", "乙", "丙", "丁", "戊", "己", "庚", "辛", "壬", "癸"}
+
+// DiZhi 十二地支（按子丑寅卯辰巳午未申酉戌亥排序）
+var DiZhi = []string{"子", "丑", "寅", "卯", "辰", "巳", "午", "未", "申", "酉", "戌", "亥"}
+
+// ShengXiao 十二生肖（与地支顺序对应）
+var ShengXiao = []string{"鼠", "牛", "虎", "兔", "龙", "蛇", "马", "羊", "猴", "鸡", "狗", "猪"}
+
+// ChangSheng 十二长生（按长生沐浴冠带临官帝旺衰病死墓绝胎养排序）
+var ChangSheng = []string{"长生", "沐浴", "冠带", "临官", "帝旺", "衰", "病", "死", "墓", "绝", "胎", "养"}
+
+// NaYin 六十甲子纳音（每两柱共用一个纳音，按甲子、丙寅……排序）
+var NaYin = []string{
+	"海中金", "炉中火", "大林木", "路旁土", "剑锋金", "山头火",
+	"涧下水", "城头土", "白蜡金", "杨柳木", "泉中水", "屋上土",
+	"霹雳火", "松柏木", "长流水", "沙中金", "山下火", "平地木",
+	"壁上土", "金箔金", "覆灯火", "天河水", "大驿土", "钗钏金",
+	"桑柘木", "大溪水", "沙中土", "天上火", "石榴木", "大海水",
+}
+
+// branchWuXing 地支五行
+var branchWuXing = []WuXing{Shui, Tu, Mu, Mu, Tu, Huo, Huo, Tu, Jin, Jin, Tu, Shui}
+
+// hiddenStems 地支藏干（按本气中气余气排序）
+var hiddenStems = [][]int{
+	{9},       // 子：癸
+	{5, 9, 7}, // 丑：己癸辛
+	{0, 2, 4}, // 寅：甲丙戊
+	{1},       // 卯：乙
+	{4, 1, 9}, // 辰：戊乙癸
+	{2, 6, 4}, // 巳：丙庚戊
+	{3, 5},    // 午：丁己
+	{5, 3, 1}, // 未：己丁乙
+	{6, 8, 4}, // 申：庚壬戊
+	{7},       // 酉：辛
+	{4, 7, 3}, // 戌：戊辛丁
+	{8, 0},    // 亥：壬甲
+}
+
+// changShengStart 十天干长生所在地支
+var changShengStart = []int{11, 6, 2, 9, 2, 9, 5, 0, 8, 3}
+
+// StemIndex 返回天干序号，无效天干返回 -1
+func StemIndex(stem string) int {
+	for i, s := range TianGan {
+		if s == stem {
+			return i
+		}
+	}
+	return -1
+}
+
+// BranchIndex 返回地支序号，无效地支返回 -1
+func BranchIndex(branch string) int {
+	for i, b := range DiZhi {
+		if b == branch {
+			return i
+		}
+	}
+	return -1
+}
+
+// StemWuXing 返回天干五行
+func StemWuXing(stem int) WuXing {
+	return WuXing(stem / 2)
+}
+
+// BranchWuXing 返回地支五行
+func BranchWuXing(branch int) WuXing {
+	return branchWuXing[branch]
+}
+
+// IsYangStem 判断天干阴阳，甲丙戊庚壬为阳
+func IsYangStem(stem int) bool {
+	return stem%2 == 0
+}
+
+// HiddenStems 返回地支藏干序号（按本气中气余气排序）
+func HiddenStems(branch int) []int {
+	return hiddenStems[branch]
+}
+
+// TenGod 计算 other 天干相对于日主 day 的十神
+func TenGod(day, other int) string {
+	dayWx, otherWx := StemWuXing(day), StemWuXing(other)
+	samePolarity := IsYangStem(day) == IsYangStem(other)
+
+	pick := func(same, diff string) string {
+		if samePolarity {
+			return same
+		}
+		return diff
+	}
+
+	switch {
+	case dayWx == otherWx:
+		return pick("比肩", "劫财")
+	case dayWx.Generates() == otherWx:
+		return pick("食神", "伤官")
+	case dayWx.Controls() == otherWx:
+		return pick("偏财", "正财")
+	case otherWx.Controls() == dayWx:
+		return pick("七杀", "正官")
+	default:
+		return pick("偏印", "正印")
+	}
+}
+
+// ChangShengIndex 计算天干在地支上的十二长生序号，阳干顺行、阴干逆行
+func ChangShengIndex(stem, branch int) int {
+	start := changShengStart[stem]
+	if IsYangStem(stem) {
+		return (branch - start + 12) % 12
+	}
+	return (start - branch + 12) % 12
+}
+
+// JiaZiIndex 返回干支在六十甲子中的序号，干支阴阳不一致时返回 -1
+func JiaZiIndex(stem, branch int) int {
+	if stem < 0 || branch < 0 || stem%2 != branch%2 {
+		return -1
+	}
+	return ((6*stem-5*branch)%60 + 60) % 60
+}
+
+// JiaZi 返回六十甲子序号对应的干支字符串
+func JiaZi(index int) string {
+	index = (index%60 + 60) % 60
+	return TianGan[index%10] + DiZhi[index%12]
+}
+
+// ParseGanZhi 将“甲子”形式的干支拆分为天干、地支序号
+func ParseGanZhi(ganzhi string) (stem, branch int, ok bool) {
+	runes := []rune(ganzhi)
+	if len(runes) != 2 {
+		return -1, -1, false
+	}
+	stem, branch = StemIndex(string(runes[0])), BranchIndex(string(runes[1]))
+	if JiaZiIndex(stem, branch) < 0 {
+		return -1, -1, false
+	}
+	return stem, branch, true
+}
+
+// NaYinOf 返回六十甲子序号对应的纳音
+func NaYinOf(index int) string {
+	return NaYin[((index%60+60)%60)/2]
+}
+
+// KongWang 返回干支所在旬的空亡地支
+func KongWang(index int) string {
+	xunBranch := (index - index%10) % 12
+	return DiZhi[(xunBranch+10)%12] + DiZhi[(xunBranch+11)%12]
+}
@@ -0,0 +1,22 @@
+package bazi
+
+import "context"
+
+// ProviderStatus 排盘提供方的运行状态
+type ProviderStatus struct {
+	Name      string `json:"name"`                 // 提供方名称
+	Reachable bool   `json:"reachable"`            // 是否可用
+	LatencyMS int64  `json:"latency_ms,omitempty"` // 探测耗时（毫秒）
+	Breaker   string `json:"breaker,omitempty"`    // 熔断器状态：closed、open、half-open
+	Quota     string `json:"quota,omitempty"`      // 剩余调用次数，提供方无法查询时为 QuotaUnsupported，不限次数时为空
+	Detail    string `json:"detail,omitempty"`     // 不可用的原因或其他说明
+}
+
+// QuotaUnsupported 表示提供方不支持查询剩余调用次数
+const QuotaUnsupported = "unsupported"
+
+// HealthChecker 可以探测自身运行状态的排盘提供方
+type HealthChecker interface {
+	// CheckHealth 轻量探测提供方是否可用，不消耗排盘次数；返回值的 Name 由调用方填写
+	CheckHealth(ctx context.Context) ProviderStatus
+}
@@ -2,19 +2,50 @@ package bazi
 
 // Request 定义了八字排盘工具的输入参数结构。
 type Request struct {
-	Name     string `json:"name" description:"姓名（字符串类型）" default:"求测者"`
-	Sex      int    `json:"sex" description:"性别 0男 1女（整数）" required:"true" enum:"0,1"`
-	Type     int    `json:"type" description:"历类型 0农历 1公历（整数）" required:"true" enum:"0,1" default:"1"`
-	Year     int    `json:"year" description:"出生年 例: 1988（整数）" required:"true"`
-	Month    int    `json:"month" description:"出生月 例: 8（整数）" required:"true"`
-	Day      int    `json:"day" description:"出生日 例: 7（整数）" required:"true"`
-	Hours    int    `json:"hours" description:"出生时 例: 12（整数）" required:"true"`
-	Minute   int    `json:"minute,omitempty" description:"出生分 例: 30（整数）" default:"0"`
-	Sect     int    `json:"sect,omitempty" description:"流派 1:晚子时日柱算明天 2:晚子时日柱算当天" default:"1"`
-	Zhen     int    `json:"zhen,omitempty" description:"是否真太阳时 1:考虑真太阳时 2:不考虑真太阳时" default:"2"`
-	Province string `json:"province,omitempty" description:"表示具体的省级行政区 最后面需要带上“省市区”等 例：北京市" x-enum:"data://provinces"`
-	City     string `json:"city,omitempty" description:"表示具体的县市级行政区 最后面一般不带上“县市区”（除非带上后只有两个字） 例：北京" x-enum:"data://cities/{province}"`
-	Lang     string `json:"lang,omitempty" description:"多语言:zh-cn、zh-tw" default:"zh-cn"`
+	Name      string   `json:"name" description:"姓名（字符串类型）" default:"求测者"`
+	Sex       int      `json:"sex" description:"性别 0男 1女（整数）" required:"true" enum:"0,1"`
+	Type      int      `json:"type" description:"历类型 0农历 1公历（整数）" required:"true" enum:"0,1" default:"1"`
+	Year      int      `json:"year" description:"出生年 例: 1988（整数）" required:"true"`
+	Month     int      `json:"month" description:"出生月 例: 8（整数）" required:"true"`
+	Day       int      `json:"day" description:"出生日 例: 7（整数）" required:"true"`
+	Leap      bool     `json:"leap,omitempty" description:"农历出生月是否为闰月，仅 type=0 时有效 例: true（布尔）" default:"false"`
+	Hours     int      `json:"hours" description:"出生时 例: 12（整数）" required:"true"`
+	Minute    int      `json:"minute,omitempty" description:"出生分 例: 30（整数）" default:"0"`
+	Sect      int      `json:"sect,omitempty" description:"流派 1:晚子时日柱算明天 2:晚子时日柱算当天" default:"1"`
+	Zhen      int      `json:"zhen,omitempty" description:"是否真太阳时 1:考虑真太阳时 2:不考虑真太阳时" default:"2"`
+	Province  string   `json:"province,omitempty" description:"表示具体的省级行政区 最后面需要带上“省市区”等 例：北京市" x-enum:"data://provinces"`
+	City      string   `json:"city,omitempty" description:"表示具体的县市级行政区 最后面一般不带上“县市区”（除非带上后只有两个字），也可填写今名、旧名或市辖区名称；未填写省份时可填写境外城市中英文名称 例：北京、通州、纽约、London" x-enum:"data://cities/{province}"`
+	Longitude *float64 `json:"longitude,omitempty" description:"出生地经度，东经为正、西经为负，需与 latitude 一同填写，填写后优先于省份城市 例: -74.01（数字）"`
+	Latitude  *float64 `json:"latitude,omitempty" description:"出生地纬度，北纬为正、南纬为负 例: 40.71（数字）"`
+	Timezone  string   `json:"timezone,omitempty" description:"出生地 IANA 时区，出生时间按该时区的当地钟表时间理解，需与经纬度一同填写，未填写时按北京时间 例：America/New_York"`
+	Lang      string   `json:"lang,omitempty" description:"多语言:zh-cn、zh-tw" default:"zh-cn"`
+	Output    string   `json:"output,omitempty" description:"输出格式 text:排盘文本 json:结构化 JSON（结构见 data://schema/chart） both:文本与 JSON" enum:"text,json,both" default:"text"`
+}
+
+// AnalyzeRequest 定义了命局分析工具的输入参数结构：排盘参数之外指定分析大运流年的年份。
+type AnalyzeRequest struct {
+	Request
+	TargetYear int `json:"target_year,omitempty" description:"流年年份：列出该年大运、流年与原局的刑冲合害，未填写时取当前年份 例: 2025（整数）"`
+}
+
+// RequestDefaults 排盘请求中未填写的流派、真太阳时与语言的默认值，零值字段不设置，由提供方按其默认值处理
+type RequestDefaults struct {
+	Sect int    // 流派 1 或 2
+	Zhen int    // 是否真太阳时 1 或 2
+	Lang string // 语言 zh-cn 或 zh-tw
+}
+
+// Apply 为请求中未填写的字段设置默认值
+func (d RequestDefaults) Apply(req *Request) {
+	if req.Sect == 0 {
+		req.Sect = d.Sect
+	}
+	if req.Zhen == 0 {
+		req.Zhen = d.Zhen
+	}
+	if req.Lang == "" {
+		req.Lang = d.Lang
+	}
 }
 
 // PaipanResponse 定义了从外部 API 获取的八字排盘响应结构。
@@ -23,4 +54,6 @@ type PaipanResponse struct {
 	ErrMsg  string `json:"errmsg"`
 	Notice  string `json:"notice"`
 	Data    Data   `json:"data"`
+	// Provider 给出本次结果的排盘提供方，由提供方组合填写，不属于提供方原始响应
+	Provider string `json:"provider,omitempty"`
 }
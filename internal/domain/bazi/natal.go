@@ -0,0 +1,91 @@
+package bazi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidBazi 八字四柱信息不完整或干支无效，无法分析
+var ErrInvalidBazi = errors.New("八字四柱信息无效")
+
+// 四柱位置
+const (
+	YearPillar = iota
+	MonthPillar
+	DayPillar
+	HourPillar
+)
+
+// PillarNames 四柱名称（按年月日时排序）
+var PillarNames = []string{"年", "月", "日", "时"}
+
+// QiNames 地支藏干的气位名称（按本气中气余气排序）
+var QiNames = []string{"本气", "中气", "余气"}
+
+// hiddenStemWeights 地支藏干按个数分配的力量，依次为本气、中气、余气，合计为 1
+var hiddenStemWeights = [][]float64{
+	{1},
+	{0.7, 0.3},
+	{0.6, 0.3, 0.1},
+}
+
+// natal 解析为天干、地支与藏干序号的原局四柱
+type natal struct {
+	stems    [4]int
+	branches [4]int
+	hidden   [4][]int // 地支藏干，按本气中气余气排序
+}
+
+// parseNatal 从八字信息解析原局四柱；藏干取 DzCg，缺失或无效时按地支推算
+func parseNatal(info BaziInfo) (natal, error) {
+	var n natal
+	if len(info.Bazi) != 4 {
+		return natal{}, fmt.Errorf("%w: 应为四柱，实际 %d 柱", ErrInvalidBazi, len(info.Bazi))
+	}
+	for i, ganzhi := range info.Bazi {
+		stem, branch, ok := ParseGanZhi(ganzhi)
+		if !ok {
+			return natal{}, fmt.Errorf("%w: %s柱干支 %q", ErrInvalidBazi, PillarNames[i], ganzhi)
+		}
+		n.stems[i], n.branches[i] = stem, branch
+		if i < len(info.DzCg) {
+			n.hidden[i] = parseStems(info.DzCg[i])
+		}
+		if len(n.hidden[i]) == 0 || len(n.hidden[i]) > len(hiddenStemWeights) {
+			n.hidden[i] = HiddenStems(branch)
+		}
+	}
+	return n, nil
+}
+
+// parseStems 取出字符串中的天干，忽略分隔符，例："己|丁|乙"
+func parseStems(s string) []int {
+	var stems []int
+	for _, r := range s {
+		if stem := StemIndex(string(r)); stem >= 0 {
+			stems = append(stems, stem)
+		}
+	}
+	return stems
+}
+
+// dayMaster 返回日主天干序号
+func (n natal) dayMaster() int {
+	return n.stems[DayPillar]
+}
+
+// hiddenWeight 返回第 i 个藏干在 count 个藏干中所占的力量
+func hiddenWeight(count, i int) float64 {
+	return hiddenStemWeights[count-1][i]
+}
+
+// round1 保留一位小数
+func round1(x float64) float64 {
+	return math.Round(x*10) / 10
+}
+
+// round2 保留两位小数
+func round2(x float64) float64 {
+	return math.Round(x*100) / 100
+}
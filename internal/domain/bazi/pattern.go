@@ -0,0 +1,326 @@
+package bazi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 格局类别
+const (
+	PatternRegular   = "正格"  // 按月令藏干透出取格
+	PatternTransform = "化气格" // 日干与紧贴的天干合化，化神当令
+	PatternMonopoly  = "专旺格" // 日主一行独旺，不见官杀
+	PatternFollow    = "从格"  // 日主无根极弱，从命局最旺的一方
+)
+
+// Confidence 格局判定的可信度
+type Confidence string
+
+const (
+	ConfidenceHigh   Confidence = "高"
+	ConfidenceMedium Confidence = "中"
+	ConfidenceLow    Confidence = "低"
+)
+
+// 特殊格局的判定界限
+const (
+	followScore   = 20 // 从格要求日主强弱得分不高于该值
+	dominantShare = 40 // 从财、从杀、从儿要求所从一方按月令调整后的占比不低于该值，否则为从势
+	monopolyShare = 80 // 专旺格要求比劫与印星按月令调整后的合计占比不低于该值
+)
+
+// monopolyNames 专旺格按日主五行的名称
+var monopolyNames = []string{"曲直格", "炎上格", "稼穑格", "从革格", "润下格"}
+
+// followNames 从格按所从一方的名称
+var followNames = map[string]string{"官杀": "从杀格", "财星": "从财格", "食伤": "从儿格"}
+
+// Pattern 一个格局及其判定依据
+type Pattern struct {
+	Name       string     `json:"name" description:"格局名称，例：正官格、从财格、化土格、曲直格"`
+	Category   string     `json:"category" description:"格局类别：正格、化气格、专旺格或从格"`
+	Rule       string     `json:"rule" description:"成立所依据的规则"`
+	Evidence   []string   `json:"evidence" description:"命局中支持该格局的事实"`
+	Confidence Confidence `json:"confidence" description:"可信度：高、中、低"`
+}
+
+// PatternCheck 一种特殊格局的检查结果
+type PatternCheck struct {
+	Category string `json:"category" description:"化气格、专旺格或从格"`
+	Matched  bool   `json:"matched" description:"是否成立"`
+	Finding  string `json:"finding" description:"成立的依据或不成立的原因"`
+}
+
+// PatternResult 格局判定结果：依次检查化气格、专旺格、从格，均不成立时取正格
+type PatternResult struct {
+	Pattern Pattern        `json:"pattern" description:"判定的格局，特殊格局成立时优先于正格"`
+	Regular Pattern        `json:"regular" description:"按月令藏干取的正格，可与提供方的八字正格对照"`
+	Checks  []PatternCheck `json:"checks" description:"化气格、专旺格、从格的检查结果，按检查顺序排列"`
+}
+
+// ClassifyPattern 判定格局，给出成立的规则、依据与可信度，八字信息无效时返回 ErrInvalidBazi
+func ClassifyPattern(info BaziInfo) (PatternResult, error) {
+	n, err := parseNatal(info)
+	if err != nil {
+		return PatternResult{}, err
+	}
+	strength, err := AnalyzeStrength(info)
+	if err != nil {
+		return PatternResult{}, err
+	}
+	elements, err := AnalyzeElements(info)
+	if err != nil {
+		return PatternResult{}, err
+	}
+	relations, err := NatalRelations(info)
+	if err != nil {
+		return PatternResult{}, err
+	}
+
+	c := patternClassifier{n: n, day: StemWuXing(n.dayMaster()), strength: strength, elements: elements, relations: relations}
+	result := PatternResult{Regular: c.regular()}
+	result.Pattern = result.Regular
+	matched := false
+	for _, check := range []func() (Pattern, PatternCheck){c.transform, c.monopoly, c.follow} {
+		pattern, pc := check()
+		if pc.Matched && !matched {
+			result.Pattern, matched = pattern, true
+		}
+		result.Checks = append(result.Checks, pc)
+	}
+	return result, nil
+}
+
+// patternClassifier 判定格局所需的命局信息
+type patternClassifier struct {
+	n         natal
+	day       WuXing
+	strength  Strength
+	elements  ElementBalance
+	relations []Relation
+}
+
+// regular 正格：月令本气为比劫时为建禄格或月刃格；否则取透出天干的月令藏干，本气优先，均未透时以本气论
+func (c *patternClassifier) regular() Pattern {
+	day := c.n.dayMaster()
+	branch, hidden := c.n.branches[MonthPillar], c.n.hidden[MonthPillar]
+	evidence := []string{fmt.Sprintf("月令%s藏%s", DiZhi[branch], stemChars(hidden))}
+	main := hidden[0]
+	switch god := TenGod(day, main); god {
+	case "比肩", "劫财":
+		name := "建禄格"
+		if god == "劫财" {
+			name = "月刃格"
+		}
+		return Pattern{
+			Name: name, Category: PatternRegular, Rule: "月令本气为" + god,
+			Evidence:   append(evidence, fmt.Sprintf("本气%s为日主%s的%s", TianGan[main], TianGan[day], god)),
+			Confidence: ConfidenceHigh,
+		}
+	}
+
+	for i, stem := range hidden {
+		god := TenGod(day, stem)
+		if god == "比肩" || god == "劫财" {
+			continue
+		}
+		positions := c.protrusions(stem)
+		if positions == "" {
+			continue
+		}
+		evidence = append(evidence, fmt.Sprintf("%s%s（%s）透于%s", QiNames[i], TianGan[stem], god, positions))
+		if i == 0 {
+			return Pattern{Name: god + "格", Category: PatternRegular, Rule: "月令本气透干", Evidence: evidence, Confidence: ConfidenceHigh}
+		}
+		evidence = append(evidence, fmt.Sprintf("本气%s未透", TianGan[main]))
+		return Pattern{Name: god + "格", Category: PatternRegular, Rule: "月令本气未透，取透干的" + QiNames[i], Evidence: evidence, Confidence: ConfidenceMedium}
+	}
+	god := TenGod(day, main)
+	evidence = append(evidence, "月令藏干均未透出天干", fmt.Sprintf("本气%s为%s", TianGan[main], god))
+	return Pattern{Name: god + "格", Category: PatternRegular, Rule: "月令藏干未透，以本气论", Evidence: evidence, Confidence: ConfidenceLow}
+}
+
+// transform 化气格：日干与月干或时干五合，不争合，化神当令且天干不见克化神之干
+func (c *patternClassifier) transform() (Pattern, PatternCheck) {
+	check := PatternCheck{Category: PatternTransform}
+	day := c.n.dayMaster()
+	partner := (day + 5) % 10
+	var adjacent []int
+	for _, p := range []int{MonthPillar, HourPillar} {
+		if c.n.stems[p] == partner {
+			adjacent = append(adjacent, p)
+		}
+	}
+	if len(adjacent) == 0 {
+		check.Finding = fmt.Sprintf("日干%s与月干%s、时干%s均不相合", TianGan[day], TianGan[c.n.stems[MonthPillar]], TianGan[c.n.stems[HourPillar]])
+		return Pattern{}, check
+	}
+	if len(adjacent) > 1 || c.n.stems[YearPillar] == partner {
+		check.Finding = fmt.Sprintf("天干%s不止一见，争合不化", TianGan[partner])
+		return Pattern{}, check
+	}
+
+	low := min(day, partner)
+	element := stemCombineElements[low]
+	pair := fmt.Sprintf("日干%s与%s干%s相合", TianGan[day], PillarNames[adjacent[0]], TianGan[partner])
+	month := DiZhi[c.n.branches[MonthPillar]]
+	if season := Season(element, BranchWuXing(c.n.branches[MonthPillar])); season != "旺" {
+		check.Finding = fmt.Sprintf("%s，化神%s在%s月%s，不得月令", pair, element, month, season)
+		return Pattern{}, check
+	}
+	controller := element.ControlledBy()
+	for p, stem := range c.n.stems {
+		if p != DayPillar && p != adjacent[0] && StemWuXing(stem) == controller {
+			check.Finding = fmt.Sprintf("%s，化神%s当令，但%s干%s%s克化神", pair, element, PillarNames[p], TianGan[stem], controller)
+			return Pattern{}, check
+		}
+	}
+
+	evidence := []string{pair, fmt.Sprintf("化神%s生于%s月，当令", element, month), fmt.Sprintf("其余天干不见%s", controller)}
+	confidence := ConfidenceHigh
+	if rooted := c.branchesOf(controller); rooted != "" {
+		evidence = append(evidence, fmt.Sprintf("%s本气为%s，化神受制", rooted, controller))
+		confidence = ConfidenceMedium
+	}
+	check.Matched, check.Finding = true, strings.Join(evidence, "，")
+	return Pattern{Name: "化" + element.String() + "格", Category: PatternTransform, Rule: "日干合化，化神当令且不受克",
+		Evidence: evidence, Confidence: confidence}, check
+}
+
+// monopoly 专旺格：日主当令，比劫与印星占绝对优势且官杀无力；官杀全无并成方成局时可信度高
+func (c *patternClassifier) monopoly() (Pattern, PatternCheck) {
+	check := PatternCheck{Category: PatternMonopoly}
+	month := DiZhi[c.n.branches[MonthPillar]]
+	if season := Season(c.day, BranchWuXing(c.n.branches[MonthPillar])); season != "旺" {
+		check.Finding = fmt.Sprintf("日主%s在%s月%s，不当令", c.day, month, season)
+		return Pattern{}, check
+	}
+	printWx, officialWx := c.day.GeneratedBy(), c.day.ControlledBy()
+	support := round1(c.share(c.day) + c.share(printWx))
+	if support < monopolyShare {
+		check.Finding = fmt.Sprintf("日主当令，比劫与印星合计占 %s%%，不足 %d%%", FormatFloat(support), monopolyShare)
+		return Pattern{}, check
+	}
+	if c.share(officialWx) >= weakShare {
+		check.Finding = fmt.Sprintf("日主当令，但官杀%s占 %s%%，不能专旺", officialWx, FormatFloat(c.share(officialWx)))
+		return Pattern{}, check
+	}
+
+	evidence := []string{fmt.Sprintf("日主%s生于%s月，当令", c.day, month), fmt.Sprintf("比劫与印星合计占 %s%%", FormatFloat(support))}
+	confidence := ConfidenceMedium
+	combined := ""
+	for _, r := range c.relations {
+		if (r.Kind == TripleCombine || r.Kind == DirectionCombine) && r.Element == c.day.String() {
+			combined = r.Name
+			break
+		}
+	}
+	if c.elements.Elements[officialWx].Total == 0 {
+		evidence = append(evidence, fmt.Sprintf("官杀%s全无", officialWx))
+		if combined != "" {
+			confidence = ConfidenceHigh
+		}
+	} else {
+		evidence = append(evidence, fmt.Sprintf("官杀%s仅占 %s%%", officialWx, FormatFloat(c.share(officialWx))))
+	}
+	if combined != "" {
+		evidence = append(evidence, "地支"+combined)
+	}
+	check.Matched, check.Finding = true, strings.Join(evidence, "，")
+	return Pattern{Name: monopolyNames[c.day], Category: PatternMonopoly, Rule: "日主当令独旺，官杀无力",
+		Evidence: evidence, Confidence: confidence}, check
+}
+
+// follow 从格：日主得分极低且地支无比劫之根，从官杀、财星、食伤中最旺的一方，三者均不突出时为从势格
+func (c *patternClassifier) follow() (Pattern, PatternCheck) {
+	check := PatternCheck{Category: PatternFollow}
+	score := FormatFloat(c.strength.Score)
+	if c.strength.Score > followScore {
+		check.Finding = fmt.Sprintf("日主强弱得分 %s，高于 %d，不能从", score, followScore)
+		return Pattern{}, check
+	}
+	if roots := c.roots(); roots != "" {
+		check.Finding = fmt.Sprintf("日主得分 %s，但%s藏比劫，有根不从", score, roots)
+		return Pattern{}, check
+	}
+
+	dominant := c.day.ControlledBy()
+	for _, w := range []WuXing{c.day.Generates(), c.day.Controls()} {
+		if c.share(w) > c.share(dominant) {
+			dominant = w
+		}
+	}
+	category := Category(c.day, dominant)
+	evidence := []string{fmt.Sprintf("日主强弱得分 %s（%s）", score, c.strength.Level), "地支藏干无比劫，日主无根",
+		fmt.Sprintf("%s%s占 %s%%", category, dominant, FormatFloat(c.share(dominant)))}
+	name, rule := followNames[category], "日主无根，从最旺的"+category
+	if c.share(dominant) < dominantShare {
+		name, rule = "从势格", "日主无根，克泄耗均不突出，从其势"
+	}
+	confidence := ConfidenceMedium
+	if c.strength.Score <= followScore/2 {
+		confidence = ConfidenceHigh
+	}
+	check.Matched, check.Finding = true, strings.Join(evidence, "，")
+	return Pattern{Name: name, Category: PatternFollow, Rule: rule, Evidence: evidence, Confidence: confidence}, check
+}
+
+// protrusions 返回藏干透出的天干位置（年干、月干、时干），例：年干、时干
+func (c *patternClassifier) protrusions(stem int) string {
+	var positions []string
+	for _, p := range []int{YearPillar, MonthPillar, HourPillar} {
+		if c.n.stems[p] == stem {
+			positions = append(positions, PillarNames[p]+"干")
+		}
+	}
+	return strings.Join(positions, "、")
+}
+
+// branchesOf 返回本气为五行 w 的地支位置，例：年支、时支
+func (c *patternClassifier) branchesOf(w WuXing) string {
+	var positions []string
+	for p := range c.n.branches {
+		if StemWuXing(c.n.hidden[p][0]) == w {
+			positions = append(positions, PillarNames[p]+"支")
+		}
+	}
+	return strings.Join(positions, "、")
+}
+
+// roots 返回藏有比劫（日主之根）的地支位置
+func (c *patternClassifier) roots() string {
+	var positions []string
+	for p := range c.n.branches {
+		for _, stem := range c.n.hidden[p] {
+			if StemWuXing(stem) == c.day {
+				positions = append(positions, PillarNames[p]+"支")
+				break
+			}
+		}
+	}
+	return strings.Join(positions, "、")
+}
+
+// share 返回五行按月令调整后的占比
+func (c *patternClassifier) share(w WuXing) float64 {
+	return c.elements.Elements[w].Share
+}
+
+// stemChars 返回藏干文本，例：甲丙戊
+func stemChars(stems []int) string {
+	var b strings.Builder
+	for _, stem := range stems {
+		b.WriteString(TianGan[stem])
+	}
+	return b.String()
+}
+
+// Summary 返回格局判定的一句话摘要
+func (r PatternResult) Summary() string {
+	p := r.Pattern
+	summary := fmt.Sprintf("%s（%s，可信度%s）：%s", p.Name, p.Category, p.Confidence, p.Rule)
+	if p.Name != r.Regular.Name {
+		summary += "；按月令正格为" + r.Regular.Name
+	}
+	return summary
+}
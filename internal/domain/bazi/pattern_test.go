@@ -0,0 +1,97 @@
+package bazi
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClassifyPatternRegular(t *testing.T) {
+	r, err := ClassifyPattern(testBaziInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Pattern{Name: "偏财格", Category: PatternRegular, Rule: "月令藏干未透，以本气论",
+		Evidence: []string{"月令子藏癸", "月令藏干均未透出天干", "本气癸为偏财"}, Confidence: ConfidenceLow}
+	if !reflect.DeepEqual(r.Pattern, want) || !reflect.DeepEqual(r.Regular, want) {
+		t.Errorf("ClassifyPattern() =\n%+v\n期望\n%+v", r, want)
+	}
+	if len(r.Checks) != 3 || r.Checks[0].Category != PatternTransform || r.Checks[1].Finding != "日主土在子月囚，不当令" || r.Checks[2].Matched {
+		t.Errorf("Checks = %+v", r.Checks)
+	}
+
+	tests := []struct {
+		bazi       []string
+		name, rule string
+		confidence Confidence
+	}{
+		{[]string{"庚申", "甲申", "乙酉", "庚辰"}, "正官格", "月令本气透干", ConfidenceHigh},
+		{[]string{"甲午", "乙亥", "庚申", "乙酉"}, "偏财格", "月令本气未透，取透干的中气", ConfidenceMedium},
+		{[]string{"甲寅", "丙寅", "甲寅", "甲子"}, "建禄格", "月令本气为比肩", ConfidenceHigh},
+	}
+	for _, tt := range tests {
+		r, err := ClassifyPattern(BaziInfo{Bazi: tt.bazi})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p := r.Pattern; p.Name != tt.name || p.Rule != tt.rule || p.Confidence != tt.confidence || p.Category != PatternRegular {
+			t.Errorf("%v: %+v", tt.bazi, p)
+		}
+	}
+
+	if _, err := ClassifyPattern(BaziInfo{Bazi: []string{"己卯", "丙子", "己未"}}); !errors.Is(err, ErrInvalidBazi) {
+		t.Errorf("四柱不全应返回 ErrInvalidBazi: %v", err)
+	}
+}
+
+func TestClassifyPatternSpecial(t *testing.T) {
+	tests := []struct {
+		bazi       []string
+		name       string
+		category   string
+		confidence Confidence
+		evidence   string
+	}{
+		// 甲己合于未月，土当令，天干无木
+		{[]string{"戊辰", "己未", "甲辰", "戊辰"}, "化土格", PatternTransform, ConfidenceHigh, "日干甲与月干己相合"},
+		// 木当令，寅卯辰会木方，不见金
+		{[]string{"甲辰", "丁卯", "甲寅", "乙亥"}, "曲直格", PatternMonopoly, ConfidenceHigh, "地支寅卯辰三会木方"},
+		// 金虽无透，申中藏庚，仍可专旺但可信度降低
+		{[]string{"戊辰", "甲寅", "乙卯", "甲申"}, "曲直格", PatternMonopoly, ConfidenceMedium, "官杀金仅占 5%"},
+		// 丙火坐子，满盘皆水
+		{[]string{"壬子", "壬子", "丙子", "庚子"}, "从杀格", PatternFollow, ConfidenceHigh, "地支藏干无比劫，日主无根"},
+	}
+	for _, tt := range tests {
+		r, err := ClassifyPattern(BaziInfo{Bazi: tt.bazi})
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := r.Pattern
+		if p.Name != tt.name || p.Category != tt.category || p.Confidence != tt.confidence || !strings.Contains(strings.Join(p.Evidence, "，"), tt.evidence) {
+			t.Errorf("%v: %+v", tt.bazi, p)
+		}
+		if r.Regular.Category != PatternRegular || !strings.Contains(r.Summary(), "按月令正格为"+r.Regular.Name) {
+			t.Errorf("%v: 正格 %+v，摘要 %q", tt.bazi, r.Regular, r.Summary())
+		}
+	}
+
+	// 争合不化、专旺不足、有根不从
+	for bazi, finding := range map[string]string{
+		"庚申 甲申 乙酉 庚辰": "天干庚不止一见，争合不化",
+		"甲寅 丙寅 甲寅 甲子": "日主当令，比劫与印星合计占 76.9%，不足 80%",
+		"庚辰 戊子 丙子 庚寅": "藏比劫，有根不从",
+	} {
+		r, err := ClassifyPattern(BaziInfo{Bazi: strings.Fields(bazi)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, c := range r.Checks {
+			found = found || (!c.Matched && strings.Contains(c.Finding, finding))
+		}
+		if !found || r.Pattern.Category != PatternRegular {
+			t.Errorf("%s: %+v", bazi, r)
+		}
+	}
+}
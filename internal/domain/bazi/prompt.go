@@ -1,29 +1,257 @@
 package bazi
 
-import "github.com/ThinkInAIXYZ/go-mcp/protocol"
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
-// 领域层维护的提示词元数据
-const (
-	PromptDescription = "八字排盘系统提示词"
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
 )
 
+// ErrInvalidPromptArgument 提示词参数无效
+var ErrInvalidPromptArgument = errors.New("提示词参数无效")
+
+// PromptTemplate 表示一类八字解读提示词
+type PromptTemplate struct {
+	Name        string   // 提示词名称
+	Title       string   // 解读主题
+	Description string   // 提示词说明
+	Focus       []string // 解读要点
+	Annual      bool     // 是否为流年解读，需要 target_year 参数
+}
+
+// PromptTemplates 全部解读提示词，bazi_prompt 为综合解读
+var PromptTemplates = []PromptTemplate{
+	{
+		Name:        "bazi_prompt",
+		Title:       "综合解读",
+		Description: "根据出生信息排盘并生成八字综合解读对话",
+		Focus: []string{
+			"日主强弱与格局，说明判断依据",
+			"喜用神与忌神，以及对应的五行、方位、颜色、行业",
+			"性格特点与天赋优势",
+			"事业财运、感情婚姻、健康方面的总体倾向",
+			"大运走势，指出关键的转折阶段",
+		},
+	},
+	{
+		Name:        "bazi_career",
+		Title:       "事业财运",
+		Description: "根据出生信息排盘并生成事业与财运解读对话",
+		Focus: []string{
+			"官杀、印星、食伤、财星的配置及其对职业方向的影响",
+			"适合的行业与工作方式（创业、就业、技术、管理等）",
+			"求财方式与理财风格，正财与偏财的取舍",
+			"事业上升或需要谨慎的大运阶段",
+		},
+	},
+	{
+		Name:        "bazi_relationship",
+		Title:       "感情婚姻",
+		Description: "根据出生信息排盘并生成感情与婚姻解读对话",
+		Focus: []string{
+			"日支夫妻宫的状态及与其他干支的合冲刑害",
+			"配偶星（男看财星、女看官杀）的强弱与位置",
+			"感情中的性格表现与相处建议",
+			"姻缘较旺或感情易有波折的大运阶段",
+		},
+	},
+	{
+		Name:        "bazi_health",
+		Title:       "健康",
+		Description: "根据出生信息排盘并生成健康倾向解读对话",
+		Focus: []string{
+			"五行偏旺与偏弱，以及对应的脏腑与身体系统",
+			"需要注意的体质倾向与养生方向",
+			"健康上需要留意的大运阶段",
+			"说明命理分析仅供参考，不能替代医学诊断",
+		},
+	},
+	{
+		Name:        "bazi_annual",
+		Title:       "流年运势",
+		Description: "根据出生信息排盘并生成指定年份的流年运势解读对话",
+		Focus: []string{
+			"流年干支与日主、原局及当前大运的生克合冲",
+			"该年事业、财运、感情、健康各方面的吉凶倾向",
+			"该年需要把握的机会与需要规避的风险",
+			"按季度或月份给出重点提示",
+		},
+		Annual: true,
+	},
+}
+
+// FindPromptTemplate 按名称查找提示词模板
+func FindPromptTemplate(name string) (PromptTemplate, bool) {
+	for _, t := range PromptTemplates {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return PromptTemplate{}, false
+}
+
 // GetPromptArguments 提供符合领域规范的参数定义（返回协议依赖需明确职责边界）
 func GetPromptArguments() []protocol.PromptArgument {
 	return []protocol.PromptArgument{
-		{
-			Name:        "birth_time",
-			Description: "出生时间，格式：YYYY-MM-DD HH:MM",
-			Required:    true,
-		},
+		{Name: "birth_time", Description: "出生时间，格式：YYYY-MM-DD HH:MM，按出生地钟表时间填写", Required: true},
+		{Name: "sex", Description: "性别：男 或 女", Required: true},
+		{Name: "name", Description: "姓名，默认“求测者”"},
+		{Name: "calendar", Description: "历法：公历（默认）或 农历"},
+		{Name: "leap", Description: "农历出生月是否为闰月：是 或 否（默认）"},
+		{Name: "province", Description: "出生省份，例：北京市，可通过 data://provinces 查询"},
+		{Name: "city", Description: "出生城市，例：北京；未填写省份时可填写境外城市，如 纽约"},
+		{Name: "zhen", Description: "是否按真太阳时排盘：是 或 否，未填写时按服务端默认配置，选“是”时需填写出生地"},
+	}
+}
+
+// Arguments 返回模板的参数定义，流年解读额外需要 target_year
+func (t PromptTemplate) Arguments() []protocol.PromptArgument {
+	args := GetPromptArguments()
+	if t.Annual {
+		args = append(args, protocol.PromptArgument{Name: "target_year", Description: "流年年份，例：2025，默认当前年份"})
 	}
+	return args
+}
+
+// PromptInput 表示解析后的提示词参数
+type PromptInput struct {
+	Request    Request // 排盘请求
+	TargetYear int     // 流年年份，0 表示未指定
 }
 
-// GeneratePromptContent 生成领域特定的提示内容（实现与协议类型的转换，迁移原提示词内容至此）
-func GeneratePromptContent() (*protocol.GetPromptResult, error) {
+// birthTimePattern 匹配 YYYY-MM-DD HH:MM，日期分隔符可为 - 或 /，日期与时间之间可为空格或 T
+var birthTimePattern = regexp.MustCompile(`^(\d{4})[-/](\d{1,2})[-/](\d{1,2})[ T](\d{1,2}):(\d{1,2})$`)
+
+// ParsePromptArguments 解析提示词参数为排盘请求，流派、真太阳时与语言未指定时留空，由服务端默认值填充
+func ParsePromptArguments(args map[string]string) (PromptInput, error) {
+	arg := func(name string) string { return strings.TrimSpace(args[name]) }
+
+	m := birthTimePattern.FindStringSubmatch(arg("birth_time"))
+	if m == nil {
+		return PromptInput{}, fmt.Errorf("%w: birth_time=%q，格式应为 YYYY-MM-DD HH:MM", ErrInvalidPromptArgument, args["birth_time"])
+	}
+	fields := make([]int, 5)
+	for i := range fields {
+		fields[i], _ = strconv.Atoi(m[i+1])
+	}
+	if fields[1] < 1 || fields[1] > 12 || fields[2] < 1 || fields[2] > 31 || fields[3] > 23 || fields[4] > 59 {
+		return PromptInput{}, fmt.Errorf("%w: birth_time=%q 超出范围", ErrInvalidPromptArgument, args["birth_time"])
+	}
+
+	input := PromptInput{Request: Request{
+		Name:     arg("name"),
+		Type:     1,
+		Year:     fields[0],
+		Month:    fields[1],
+		Day:      fields[2],
+		Hours:    fields[3],
+		Minute:   fields[4],
+		Province: arg("province"),
+		City:     arg("city"),
+	}}
+
+	switch strings.ToLower(arg("sex")) {
+	case "男", "0", "male", "m":
+		input.Request.Sex = 0
+	case "女", "1", "female", "f":
+		input.Request.Sex = 1
+	default:
+		return PromptInput{}, fmt.Errorf("%w: sex=%q，应为 男 或 女", ErrInvalidPromptArgument, args["sex"])
+	}
+
+	switch strings.ToLower(arg("calendar")) {
+	case "", "公历", "阳历", "solar", "1":
+	case "农历", "阴历", "lunar", "0":
+		input.Request.Type = 0
+	default:
+		return PromptInput{}, fmt.Errorf("%w: calendar=%q，应为 公历 或 农历", ErrInvalidPromptArgument, args["calendar"])
+	}
+
+	leap, err := parsePromptBool("leap", arg("leap"))
+	if err != nil {
+		return PromptInput{}, err
+	}
+	input.Request.Leap = leap && input.Request.Type == 0
+
+	if value := arg("zhen"); value != "" {
+		zhen, err := parsePromptBool("zhen", value)
+		if err != nil {
+			return PromptInput{}, err
+		}
+		input.Request.Zhen = 2
+		if zhen {
+			input.Request.Zhen = 1
+		}
+	}
+
+	if year := arg("target_year"); year != "" {
+		input.TargetYear, err = strconv.Atoi(strings.TrimSuffix(year, "年"))
+		if err != nil || input.TargetYear < 1 {
+			return PromptInput{}, fmt.Errorf("%w: target_year=%q，应为年份数字", ErrInvalidPromptArgument, year)
+		}
+	}
+	return input, nil
+}
+
+// parsePromptBool 解析“是/否”类参数，空值视为否
+func parsePromptBool(name, value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "", "否", "false", "no", "0":
+		return false, nil
+	case "是", "true", "yes", "1":
+		return true, nil
+	}
+	return false, fmt.Errorf("%w: %s=%q，应为 是 或 否", ErrInvalidPromptArgument, name, value)
+}
+
+// YearGanZhi 返回公历年份对应的流年干支（流年以立春交接，此处按年份整体计）
+func YearGanZhi(year int) string {
+	return JiaZi(((year-4)%60 + 60) % 60)
+}
+
+// GeneratePromptContent 生成领域特定的提示内容：先约定解读原则，再提供排盘结果与本次解读要点
+func GeneratePromptContent(t PromptTemplate, input PromptInput, chartText string) (*protocol.GetPromptResult, error) {
+	if t.Annual && input.TargetYear == 0 {
+		return nil, fmt.Errorf("%w: 流年解读需要 target_year", ErrInvalidPromptArgument)
+	}
+
+	name := input.Request.Name
+	if name == "" {
+		name = "求测者"
+	}
+	title := t.Title
+	if t.Annual {
+		title = fmt.Sprintf("%d年（%s年）%s", input.TargetYear, YearGanZhi(input.TargetYear), t.Title)
+	}
+
+	var focus strings.Builder
+	for i, item := range t.Focus {
+		fmt.Fprintf(&focus, "%d. %s\n", i+1, item)
+	}
+
 	return &protocol.GetPromptResult{
-		Description: "八字排盘系统提示",
-		Messages:    []protocol.PromptMessage{
-			// 迁移原提示词内容至此...
+		Description: fmt.Sprintf("%s的八字%s", name, title),
+		Messages: []protocol.PromptMessage{
+			{
+				Role: protocol.RoleUser,
+				Content: &protocol.TextContent{Type: "text", Text: "你是一位精通子平八字的命理分析师。请遵循以下原则进行解读：\n" +
+					"1. 以我提供的排盘结果为准，不自行重新排盘，不臆造排盘中没有的数据；\n" +
+					"2. 先说明判断依据（十神、五行旺衰、合冲刑害、大运流年），再给出结论；\n" +
+					"3. 语气客观平和，指出趋势与建议，避免绝对化和宿命论的表述；\n" +
+					"4. 涉及健康、投资等问题时提醒仅供参考，不能替代专业意见。"},
+			},
+			{
+				Role:    protocol.RoleAssistant,
+				Content: &protocol.TextContent{Type: "text", Text: "明白，我会严格依据排盘结果进行分析。请提供排盘数据和需要解读的方面。"},
+			},
+			{
+				Role: protocol.RoleUser,
+				Content: &protocol.TextContent{Type: "text", Text: fmt.Sprintf("以下是%s的八字排盘结果：\n\n%s\n\n请进行%s，重点分析：\n%s",
+					name, strings.TrimSpace(chartText), title, focus.String())},
+			},
 		},
 	}, nil
 }
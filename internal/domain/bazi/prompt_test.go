@@ -0,0 +1,78 @@
+package bazi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ThinkInAIXYZ/go-mcp/protocol"
+)
+
+func TestParsePromptArguments(t *testing.T) {
+	input, err := ParsePromptArguments(map[string]string{
+		"birth_time":  "2023-2-1 08:30",
+		"sex":         "女",
+		"calendar":    "农历",
+		"leap":        "是",
+		"zhen":        "是",
+		"province":    "北京市",
+		"city":        "北京",
+		"target_year": "2025",
+	})
+	if err != nil {
+		t.Fatalf("ParsePromptArguments() error = %v", err)
+	}
+	want := Request{Sex: 1, Type: 0, Year: 2023, Month: 2, Day: 1, Leap: true, Hours: 8, Minute: 30, Zhen: 1, Province: "北京市", City: "北京"}
+	if input.Request != want || input.TargetYear != 2025 {
+		t.Errorf("ParsePromptArguments() = %+v, want %+v", input, want)
+	}
+
+	// 未填写 zhen 时留空交由默认值，明确选“否”时按北京时间排盘
+	for value, zhen := range map[string]int{"": 0, "否": 2} {
+		input, err := ParsePromptArguments(map[string]string{"birth_time": "1990-01-01 08:00", "sex": "男", "zhen": value})
+		if err != nil || input.Request.Zhen != zhen || input.Request.Sect != 0 {
+			t.Errorf("zhen=%q: %+v, %v", value, input.Request, err)
+		}
+	}
+
+	invalid := []map[string]string{
+		{"birth_time": "1990年1月1日", "sex": "男"},
+		{"birth_time": "1990-13-01 08:00", "sex": "男"},
+		{"birth_time": "1990-01-01 08:00", "sex": "未知"},
+		{"birth_time": "1990-01-01 08:00", "sex": "男", "zhen": "也许"},
+		{"birth_time": "1990-01-01 08:00", "sex": "男", "calendar": "藏历"},
+	}
+	for _, args := range invalid {
+		if _, err := ParsePromptArguments(args); !errors.Is(err, ErrInvalidPromptArgument) {
+			t.Errorf("ParsePromptArguments(%v) error = %v, want ErrInvalidPromptArgument", args, err)
+		}
+	}
+}
+
+func TestGeneratePromptContent(t *testing.T) {
+	template, ok := FindPromptTemplate("bazi_annual")
+	if !ok {
+		t.Fatal("应存在流年解读提示词")
+	}
+	input := PromptInput{Request: Request{Name: "张三"}, TargetYear: 2025}
+	result, err := GeneratePromptContent(template, input, "八字：己卯 丙子 己未 丙寅")
+	if err != nil {
+		t.Fatalf("GeneratePromptContent() error = %v", err)
+	}
+	if len(result.Messages) != 3 || result.Messages[1].Role != protocol.RoleAssistant {
+		t.Fatalf("应生成三条消息, got %+v", result.Messages)
+	}
+	text := result.Messages[2].Content.(*protocol.TextContent).Text
+	for _, want := range []string{"张三", "己卯 丙子 己未 丙寅", "2025年（乙巳年）流年运势"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("提示内容缺少 %q:\n%s", want, text)
+		}
+	}
+
+	if _, err := GeneratePromptContent(template, PromptInput{}, ""); !errors.Is(err, ErrInvalidPromptArgument) {
+		t.Errorf("流年解读缺少年份时应返回错误, got %v", err)
+	}
+	if len(template.Arguments()) != len(GetPromptArguments())+1 {
+		t.Error("流年解读应额外提供 target_year 参数")
+	}
+}
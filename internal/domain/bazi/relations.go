@@ -0,0 +1,342 @@
+package bazi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RelationKind 干支关系的类别
+type RelationKind string
+
+// 干支关系类别，按输出顺序排列
+const (
+	StemCombine      RelationKind = "天干五合"
+	StemClash        RelationKind = "天干相冲"
+	SixCombine       RelationKind = "地支六合"
+	TripleCombine    RelationKind = "三合"
+	DirectionCombine RelationKind = "三会"
+	HalfCombine      RelationKind = "半合"
+	SixClash         RelationKind = "六冲"
+	Punishment       RelationKind = "三刑"
+	SelfPunishment   RelationKind = "自刑"
+	SixHarm          RelationKind = "六害"
+	SixBreak         RelationKind = "六破"
+)
+
+// 大运、流年在关系中的柱名
+const (
+	DayunPillar   = "大运"
+	LiuNianPillar = "流年"
+)
+
+// Relation 一组干支之间的合冲刑害破关系
+type Relation struct {
+	Kind    RelationKind     `json:"kind" description:"关系类别：天干五合、天干相冲、地支六合、三合、三会、半合、六冲、三刑、自刑、六害、六破"`
+	Name    string           `json:"name" description:"关系名称，例：甲己合土、申子辰三合水局、子卯无礼之刑"`
+	Members []RelationMember `json:"members" description:"参与关系的干支，按年、月、日、时、大运、流年排序"`
+	Element string           `json:"element,omitempty" description:"合化或成局的五行，冲刑害破为空"`
+}
+
+// RelationMember 参与关系的一个天干或地支
+type RelationMember struct {
+	Pillar   string `json:"pillar" description:"所在柱：年、月、日、时、大运、流年"`
+	Position string `json:"position" description:"柱位，例：年干、月支、流年支"`
+	Char     string `json:"char" description:"天干或地支"`
+}
+
+// Transit 与原局比较的大运或流年干支
+type Transit struct {
+	Pillar string // 柱名：DayunPillar 或 LiuNianPillar
+	Ganzhi string // 干支，例：乙亥
+}
+
+// stemCombineElements 天干五合所化五行，按合中阳干序号排列：甲己土、乙庚金、丙辛水、丁壬木、戊癸火
+var stemCombineElements = []WuXing{Tu, Jin, Shui, Mu, Huo}
+
+// sixCombineElements 地支六合所化五行，以两支中序号较小者为键：子丑土、寅亥木、卯戌火、辰酉金、巳申水、午未土
+var sixCombineElements = map[int]WuXing{0: Tu, 2: Mu, 3: Huo, 4: Jin, 5: Shui, 6: Tu}
+
+// branchFrame 由三个地支组成的局、方或刑
+type branchFrame struct {
+	branches [3]int
+	element  WuXing
+	name     string
+}
+
+// tripleCombines 三合局，中间一支为帝旺
+var tripleCombines = []branchFrame{
+	{branches: [3]int{8, 0, 4}, element: Shui}, // 申子辰
+	{branches: [3]int{11, 3, 7}, element: Mu},  // 亥卯未
+	{branches: [3]int{2, 6, 10}, element: Huo}, // 寅午戌
+	{branches: [3]int{5, 9, 1}, element: Jin},  // 巳酉丑
+}
+
+// directionCombines 三会方
+var directionCombines = []branchFrame{
+	{branches: [3]int{2, 3, 4}, element: Mu},    // 寅卯辰
+	{branches: [3]int{5, 6, 7}, element: Huo},   // 巳午未
+	{branches: [3]int{8, 9, 10}, element: Jin},  // 申酉戌
+	{branches: [3]int{11, 0, 1}, element: Shui}, // 亥子丑
+}
+
+// punishments 三刑，三支中任意两支亦相刑
+var punishments = []branchFrame{
+	{branches: [3]int{2, 5, 8}, name: "无恩之刑"},  // 寅巳申
+	{branches: [3]int{1, 10, 7}, name: "恃势之刑"}, // 丑戌未
+}
+
+// selfPunishments 自刑地支：辰、午、酉、亥
+var selfPunishments = map[int]bool{4: true, 6: true, 9: true, 11: true}
+
+// sixBreaks 六破：子酉、丑辰、寅亥、卯午、巳申、未戌
+var sixBreaks = map[[2]int]bool{{0, 9}: true, {1, 4}: true, {2, 11}: true, {3, 6}: true, {5, 8}: true, {7, 10}: true}
+
+// relationSlot 参与关系判断的一个天干或地支
+type relationSlot struct {
+	pillar string
+	stem   bool // 是否为天干
+	index  int  // 天干或地支序号
+	natal  bool // 是否属于原局
+}
+
+// member 返回参与关系的干支
+func (s relationSlot) member() RelationMember {
+	if s.stem {
+		return RelationMember{Pillar: s.pillar, Position: s.pillar + "干", Char: TianGan[s.index]}
+	}
+	return RelationMember{Pillar: s.pillar, Position: s.pillar + "支", Char: DiZhi[s.index]}
+}
+
+// NatalRelations 找出原局四柱之间的合冲刑害破，八字信息无效时返回 ErrInvalidBazi
+func NatalRelations(info BaziInfo) ([]Relation, error) {
+	stems, branches, err := relationSlots(info, nil)
+	if err != nil {
+		return nil, err
+	}
+	return findRelations(stems, branches, false), nil
+}
+
+// TransitRelations 找出大运、流年干支与原局四柱之间的合冲刑害破，
+// 每组关系至少包含一个原局干支和一个大运或流年干支；原局内部的关系见 NatalRelations。
+// 八字信息或大运流年干支无效时返回 ErrInvalidBazi
+func TransitRelations(info BaziInfo, transits ...Transit) ([]Relation, error) {
+	stems, branches, err := relationSlots(info, transits)
+	if err != nil {
+		return nil, err
+	}
+	return findRelations(stems, branches, true), nil
+}
+
+// relationSlots 依次列出原局四柱与大运流年的天干、地支
+func relationSlots(info BaziInfo, transits []Transit) (stems, branches []relationSlot, err error) {
+	n, err := parseNatal(info)
+	if err != nil {
+		return nil, nil, err
+	}
+	for p := range n.stems {
+		stems = append(stems, relationSlot{pillar: PillarNames[p], stem: true, index: n.stems[p], natal: true})
+		branches = append(branches, relationSlot{pillar: PillarNames[p], index: n.branches[p], natal: true})
+	}
+	for _, t := range transits {
+		stem, branch, ok := ParseGanZhi(t.Ganzhi)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s干支 %q", ErrInvalidBazi, t.Pillar, t.Ganzhi)
+		}
+		stems = append(stems, relationSlot{pillar: t.Pillar, stem: true, index: stem})
+		branches = append(branches, relationSlot{pillar: t.Pillar, index: branch})
+	}
+	return stems, branches, nil
+}
+
+// relationFinder 按类别收集干支关系
+type relationFinder struct {
+	transit   bool // 只保留原局与大运流年之间的关系
+	relations map[RelationKind][]Relation
+	covered   map[[2]int]bool // 已组成三合、三刑的两支，不再单独计为半合、相刑
+}
+
+// findRelations 找出各干支之间的关系，按类别顺序排列
+func findRelations(stems, branches []relationSlot, transit bool) []Relation {
+	f := relationFinder{transit: transit, relations: map[RelationKind][]Relation{}, covered: map[[2]int]bool{}}
+	f.stemPairs(stems)
+	f.branchTriples(branches)
+	f.branchPairs(branches)
+
+	relations := []Relation{}
+	for _, kind := range []RelationKind{StemCombine, StemClash, SixCombine, TripleCombine, DirectionCombine, HalfCombine,
+		SixClash, Punishment, SelfPunishment, SixHarm, SixBreak} {
+		relations = append(relations, f.relations[kind]...)
+	}
+	return relations
+}
+
+// add 记录一组关系，大运流年模式下跳过全部来自原局或全部来自大运流年的组合
+func (f *relationFinder) add(kind RelationKind, name string, element *WuXing, slots ...relationSlot) {
+	if f.transit {
+		natal := 0
+		for _, s := range slots {
+			if s.natal {
+				natal++
+			}
+		}
+		if natal == 0 || natal == len(slots) {
+			return
+		}
+	}
+	r := Relation{Kind: kind, Name: name}
+	for _, s := range slots {
+		r.Members = append(r.Members, s.member())
+	}
+	if element != nil {
+		r.Element = element.String()
+	}
+	f.relations[kind] = append(f.relations[kind], r)
+}
+
+// stemPairs 天干五合与相冲
+func (f *relationFinder) stemPairs(stems []relationSlot) {
+	for i := range stems {
+		for j := i + 1; j < len(stems); j++ {
+			a, b := stems[i], stems[j]
+			low, high := a.index, b.index
+			if low > high {
+				low, high = high, low
+			}
+			name := TianGan[low] + TianGan[high]
+			switch high - low {
+			case 5:
+				element := stemCombineElements[low]
+				f.add(StemCombine, name+"合"+element.String(), &element, a, b)
+			case 6:
+				f.add(StemClash, name+"相冲", nil, a, b)
+			}
+		}
+	}
+}
+
+// branchTriples 三合、三会与三刑，组成三合、三刑的两支记为已覆盖
+func (f *relationFinder) branchTriples(branches []relationSlot) {
+	for i := range branches {
+		for j := i + 1; j < len(branches); j++ {
+			for k := j + 1; k < len(branches); k++ {
+				slots := []relationSlot{branches[i], branches[j], branches[k]}
+				values := []int{slots[0].index, slots[1].index, slots[2].index}
+				if frame, ok := matchFrame(tripleCombines, values); ok {
+					f.add(TripleCombine, frameChars(frame)+"三合"+frame.element.String()+"局", &frame.element, slots...)
+					f.cover(i, j, k)
+				}
+				if frame, ok := matchFrame(directionCombines, values); ok {
+					f.add(DirectionCombine, frameChars(frame)+"三会"+frame.element.String()+"方", &frame.element, slots...)
+				}
+				if frame, ok := matchFrame(punishments, values); ok {
+					f.add(Punishment, frameChars(frame)+frame.name, nil, slots...)
+					f.cover(i, j, k)
+				}
+			}
+		}
+	}
+}
+
+// cover 记录三支中任意两支已组成三合或三刑
+func (f *relationFinder) cover(i, j, k int) {
+	f.covered[[2]int{i, j}], f.covered[[2]int{i, k}], f.covered[[2]int{j, k}] = true, true, true
+}
+
+// branchPairs 地支六合、半合、六冲、相刑、自刑、六害与六破
+func (f *relationFinder) branchPairs(branches []relationSlot) {
+	for i := range branches {
+		for j := i + 1; j < len(branches); j++ {
+			a, b := branches[i], branches[j]
+			low, high := a.index, b.index
+			if low > high {
+				low, high = high, low
+			}
+			name := DiZhi[low] + DiZhi[high]
+			if (low+high)%12 == 1 {
+				element := sixCombineElements[low]
+				f.add(SixCombine, name+"合"+element.String(), &element, a, b)
+			}
+			if frame, first, second, ok := halfFrame(a.index, b.index); ok && !f.covered[[2]int{i, j}] {
+				f.add(HalfCombine, DiZhi[first]+DiZhi[second]+"半合"+frame.element.String()+"局", &frame.element, a, b)
+			}
+			if high-low == 6 {
+				f.add(SixClash, name+"相冲", nil, a, b)
+			}
+			if low == 0 && high == 3 {
+				f.add(Punishment, name+"无礼之刑", nil, a, b)
+			} else if frame, ok := matchFrame(punishments, []int{a.index, b.index}); ok && !f.covered[[2]int{i, j}] {
+				f.add(Punishment, name+frame.name, nil, a, b)
+			}
+			if low == high && selfPunishments[low] {
+				f.add(SelfPunishment, name+"自刑", nil, a, b)
+			}
+			if (low+high)%12 == 7 {
+				f.add(SixHarm, name+"相害", nil, a, b)
+			}
+			if sixBreaks[[2]int{low, high}] {
+				f.add(SixBreak, name+"相破", nil, a, b)
+			}
+		}
+	}
+}
+
+// matchFrame 返回由 values 中互不相同的地支组成的局、方或刑，values 为两支时须同属一组
+func matchFrame(frames []branchFrame, values []int) (branchFrame, bool) {
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i] == sorted[i-1] {
+			return branchFrame{}, false
+		}
+	}
+	for _, frame := range frames {
+		matched := 0
+		for _, v := range sorted {
+			for _, b := range frame.branches {
+				if v == b {
+					matched++
+				}
+			}
+		}
+		if matched == len(sorted) {
+			return frame, true
+		}
+	}
+	return branchFrame{}, false
+}
+
+// halfFrame 判断两支是否组成半合：须同属一个三合局且含帝旺之支（生旺或旺墓），
+// 返回三合局及按局中顺序排列的两支
+func halfFrame(a, b int) (frame branchFrame, first, second int, ok bool) {
+	frame, ok = matchFrame(tripleCombines, []int{a, b})
+	if !ok || (a != frame.branches[1] && b != frame.branches[1]) {
+		return branchFrame{}, 0, 0, false
+	}
+	if a == frame.branches[1] {
+		a, b = b, a
+	}
+	// a 为生地或墓地，b 为帝旺
+	if a == frame.branches[0] {
+		return frame, a, b, true
+	}
+	return frame, b, a, true
+}
+
+// frameChars 返回局、方或刑的三个地支，例：申子辰
+func frameChars(frame branchFrame) string {
+	var b strings.Builder
+	for _, branch := range frame.branches {
+		b.WriteString(DiZhi[branch])
+	}
+	return b.String()
+}
+
+// Positions 返回参与关系的柱位与干支，例：年支卯、日支未
+func (r Relation) Positions() string {
+	items := make([]string, len(r.Members))
+	for i, m := range r.Members {
+		items[i] = m.Position + m.Char
+	}
+	return strings.Join(items, "、")
+}
@@ -0,0 +1,75 @@
+package bazi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// relationNames 返回关系名称，便于比较
+func relationNames(relations []Relation) []string {
+	names := []string{}
+	for _, r := range relations {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+func TestNatalRelations(t *testing.T) {
+	relations, err := NatalRelations(testBaziInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Relation{
+		{Kind: HalfCombine, Name: "卯未半合木局", Element: "木", Members: []RelationMember{{Pillar: "年", Position: "年支", Char: "卯"}, {Pillar: "日", Position: "日支", Char: "未"}}},
+		{Kind: Punishment, Name: "子卯无礼之刑", Members: []RelationMember{{Pillar: "年", Position: "年支", Char: "卯"}, {Pillar: "月", Position: "月支", Char: "子"}}},
+		{Kind: SixHarm, Name: "子未相害", Members: []RelationMember{{Pillar: "月", Position: "月支", Char: "子"}, {Pillar: "日", Position: "日支", Char: "未"}}},
+	}
+	if !reflect.DeepEqual(relations, want) {
+		t.Errorf("NatalRelations() =\n%+v\n期望\n%+v", relations, want)
+	}
+
+	// 甲寅 庚午 壬申 丁巳：寅巳申三刑俱全时不再单列两两相刑
+	relations, err = NatalRelations(BaziInfo{Bazi: []string{"甲寅", "庚午", "壬申", "丁巳"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{"丁壬合木", "甲庚相冲", "巳申合水", "寅午半合火局", "寅申相冲", "寅巳申无恩之刑", "寅巳相害", "巳申相破"}
+	if got := relationNames(relations); !reflect.DeepEqual(got, names) {
+		t.Errorf("NatalRelations() = %q，期望 %q", got, names)
+	}
+	if r := relations[5]; r.Kind != Punishment || len(r.Members) != 3 || r.Element != "" || r.Positions() != "年支寅、日支申、时支巳" {
+		t.Errorf("三刑 = %+v", r)
+	}
+
+	if _, err := NatalRelations(BaziInfo{Bazi: []string{"己卯", "丙子"}}); !errors.Is(err, ErrInvalidBazi) {
+		t.Errorf("四柱不全应返回 ErrInvalidBazi: %v", err)
+	}
+}
+
+func TestTransitRelations(t *testing.T) {
+	relations, err := TransitRelations(testBaziInfo(), Transit{Pillar: DayunPillar, Ganzhi: "庚辰"}, Transit{Pillar: LiuNianPillar, Ganzhi: "甲申"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 大运庚与流年甲相冲、原局子卯相刑均不计入
+	names := []string{"甲己合土", "甲己合土", "申子辰三合水局", "寅卯辰三会木方", "寅申相冲", "寅申无恩之刑", "卯辰相害"}
+	if got := relationNames(relations); !reflect.DeepEqual(got, names) {
+		t.Errorf("TransitRelations() = %q，期望 %q", got, names)
+	}
+	if r := relations[2]; r.Kind != TripleCombine || r.Element != "水" || r.Positions() != "月支子、大运支辰、流年支申" {
+		t.Errorf("三合 = %+v", r)
+	}
+
+	relations, err = TransitRelations(BaziInfo{Bazi: []string{"甲寅", "庚午", "壬申", "丁巳"}}, Transit{Pillar: LiuNianPillar, Ganzhi: "丙午"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := relationNames(relations); !reflect.DeepEqual(got, []string{"丙壬相冲", "寅午半合火局", "午午自刑"}) {
+		t.Errorf("TransitRelations() = %q", got)
+	}
+
+	if _, err := TransitRelations(testBaziInfo(), Transit{Pillar: DayunPillar, Ganzhi: "甲丑"}); !errors.Is(err, ErrInvalidBazi) {
+		t.Errorf("大运干支无效应返回 ErrInvalidBazi: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package bazi
+
+// 日主强弱的评分：得令看月支藏干，得地看其余三支藏干，得势看其余三干，
+// 生扶日主（比劫、印星）的部分计分，合计满分 100，50 为中和
+
+// lingPoints 得令满分，月令对日主强弱的影响最大
+const lingPoints = 40
+
+// diPoints 得地各柱地支的满分，日支最近日主
+var diPoints = map[int]float64{YearPillar: 10, DayPillar: 15, HourPillar: 10}
+
+// shiPoints 得势各柱天干的满分，月干、时干紧贴日主
+var shiPoints = map[int]float64{YearPillar: 7, MonthPillar: 9, HourPillar: 9}
+
+// 日主强弱等级
+const (
+	LevelStrong       = "身强"
+	LevelSlightStrong = "偏强"
+	LevelBalanced     = "中和"
+	LevelSlightWeak   = "偏弱"
+	LevelWeak         = "身弱"
+)
+
+// Strength 日主强弱分析
+type Strength struct {
+	DayMaster string           `json:"day_master" description:"日主天干"`
+	Element   string           `json:"element" description:"日主五行"`
+	Season    string           `json:"season" description:"日主在月令的旺衰：旺、相、休、囚、死"`
+	Score     float64          `json:"score" description:"强弱得分，0 到 100，50 为中和"`
+	Level     string           `json:"level" description:"身强、偏强、中和、偏弱或身弱"`
+	Factors   []StrengthFactor `json:"factors" description:"得令、得地、得势的得分明细"`
+}
+
+// StrengthFactor 得令、得地或得势一项的得分
+type StrengthFactor struct {
+	Name  string         `json:"name" description:"得令、得地或得势"`
+	Score float64        `json:"score" description:"得分"`
+	Max   float64        `json:"max" description:"满分"`
+	Items []StrengthItem `json:"items" description:"各干或藏干的得分"`
+}
+
+// StrengthItem 一个天干或藏干对日主强弱的贡献
+type StrengthItem struct {
+	Position string  `json:"position" description:"位置，例：年干、月支本气"`
+	Stem     string  `json:"stem" description:"天干或藏干"`
+	TenGod   string  `json:"ten_god" description:"相对日主的十神"`
+	Max      float64 `json:"max" description:"该位置的满分，藏干按本气、中气、余气分配"`
+	Score    float64 `json:"score" description:"得分，生扶日主时为满分，否则为 0"`
+}
+
+// AnalyzeStrength 按得令、得地、得势为日主强弱评分，八字信息无效时返回 ErrInvalidBazi
+func AnalyzeStrength(info BaziInfo) (Strength, error) {
+	n, err := parseNatal(info)
+	if err != nil {
+		return Strength{}, err
+	}
+	day := n.dayMaster()
+	dayWx := StemWuXing(day)
+
+	ling := StrengthFactor{Name: "得令"}
+	ling.addBranch(day, MonthPillar, n.hidden[MonthPillar], lingPoints)
+	di := StrengthFactor{Name: "得地"}
+	for _, p := range []int{YearPillar, DayPillar, HourPillar} {
+		di.addBranch(day, p, n.hidden[p], diPoints[p])
+	}
+	shi := StrengthFactor{Name: "得势"}
+	for _, p := range []int{YearPillar, MonthPillar, HourPillar} {
+		shi.add(day, PillarNames[p]+"干", n.stems[p], shiPoints[p])
+	}
+
+	s := Strength{
+		DayMaster: TianGan[day],
+		Element:   dayWx.String(),
+		Season:    Season(dayWx, BranchWuXing(n.branches[MonthPillar])),
+		Factors:   []StrengthFactor{ling, di, shi},
+	}
+	for i := range s.Factors {
+		s.Factors[i].Score, s.Factors[i].Max = round1(s.Factors[i].Score), round1(s.Factors[i].Max)
+		s.Score += s.Factors[i].Score
+	}
+	s.Score = round1(s.Score)
+	s.Level = strengthLevel(s.Score)
+	return s, nil
+}
+
+// addBranch 按本气、中气、余气分配地支的满分并逐一计分
+func (f *StrengthFactor) addBranch(day, pillar int, hidden []int, points float64) {
+	for i, stem := range hidden {
+		f.add(day, PillarNames[pillar]+"支"+QiNames[i], stem, points*hiddenWeight(len(hidden), i))
+	}
+}
+
+// add 计入一个天干，生扶日主时得满分
+func (f *StrengthFactor) add(day int, position string, stem int, points float64) {
+	item := StrengthItem{Position: position, Stem: TianGan[stem], TenGod: TenGod(day, stem), Max: round1(points)}
+	if supports(day, stem) {
+		item.Score = item.Max
+		f.Score += points
+	}
+	f.Max += points
+	f.Items = append(f.Items, item)
+}
+
+// supports 判断天干是否生扶日主：与日主同五行（比劫）或生日主（印星）
+func supports(day, stem int) bool {
+	dayWx, wx := StemWuXing(day), StemWuXing(stem)
+	return wx == dayWx || wx.Generates() == dayWx
+}
+
+// Season 返回五行 w 在当令五行 season 下的旺衰：当令者旺，令生者相，生令者休，克令者囚，令克者死
+func Season(w, season WuXing) string {
+	switch {
+	case w == season:
+		return "旺"
+	case season.Generates() == w:
+		return "相"
+	case w.Generates() == season:
+		return "休"
+	case w.Controls() == season:
+		return "囚"
+	default:
+		return "死"
+	}
+}
+
+// strengthLevel 按得分划分强弱等级
+func strengthLevel(score float64) string {
+	switch {
+	case score >= 70:
+		return LevelStrong
+	case score >= 55:
+		return LevelSlightStrong
+	case score > 45:
+		return LevelBalanced
+	case score > 30:
+		return LevelSlightWeak
+	default:
+		return LevelWeak
+	}
+}
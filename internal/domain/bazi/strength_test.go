@@ -0,0 +1,62 @@
+package bazi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// testBaziInfo 己卯 丙子 己未 丙寅 的八字信息
+func testBaziInfo() BaziInfo {
+	return BaziInfo{
+		Bazi: []string{"己卯", "丙子", "己未", "丙寅"},
+		DzCg: []string{"乙", "癸", "己|丁|乙", "甲|丙|戊"},
+	}
+}
+
+func TestAnalyzeStrength(t *testing.T) {
+	s, err := AnalyzeStrength(testBaziInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.DayMaster != "己" || s.Element != "土" || s.Season != "囚" || s.Score != 42.5 || s.Level != LevelSlightWeak {
+		t.Errorf("AnalyzeStrength() = %+v", s)
+	}
+	scores := make(map[string][2]float64)
+	for _, f := range s.Factors {
+		scores[f.Name] = [2]float64{f.Score, f.Max}
+	}
+	if want := map[string][2]float64{"得令": {0, 40}, "得地": {17.5, 35}, "得势": {25, 25}}; !reflect.DeepEqual(scores, want) {
+		t.Errorf("各项得分 = %v，期望 %v", scores, want)
+	}
+	if item := s.Factors[1].Items[1]; item != (StrengthItem{Position: "日支本气", Stem: "己", TenGod: "比肩", Max: 9, Score: 9}) {
+		t.Errorf("日支本气 = %+v", item)
+	}
+
+	// 缺少藏干时按地支推算
+	info := testBaziInfo()
+	info.DzCg = nil
+	if fallback, err := AnalyzeStrength(info); err != nil || !reflect.DeepEqual(fallback, s) {
+		t.Errorf("按地支推算藏干 = %+v, %v", fallback, err)
+	}
+
+	strong, err := AnalyzeStrength(BaziInfo{Bazi: []string{"甲寅", "丙寅", "甲寅", "甲子"}})
+	if err != nil || strong.Score != 65 || strong.Level != LevelSlightStrong || strong.Season != "旺" {
+		t.Errorf("AnalyzeStrength() = %+v, %v", strong, err)
+	}
+
+	for _, bazi := range [][]string{{"己卯", "丙子", "己未"}, {"己卯", "丙子", "己午", "丙寅"}} {
+		if _, err := AnalyzeStrength(BaziInfo{Bazi: bazi}); !errors.Is(err, ErrInvalidBazi) {
+			t.Errorf("AnalyzeStrength(%v) error = %v", bazi, err)
+		}
+	}
+}
+
+func TestSeason(t *testing.T) {
+	want := map[WuXing]string{Mu: "旺", Huo: "相", Tu: "死", Jin: "囚", Shui: "休"}
+	for w, season := range want {
+		if got := Season(w, Mu); got != season {
+			t.Errorf("Season(%s, 木) = %s, want %s", w, got, season)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package bazi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// School 取用神的流派
+type School string
+
+const (
+	SchoolFuYi     School = "扶抑" // 日主强则抑、弱则扶
+	SchoolTiaoHou  School = "调候" // 冬生取火暖局，夏生取水润局
+	SchoolTongGuan School = "通关" // 两行相战，取居中相生者化解
+)
+
+// DefaultSchools 默认只按扶抑取用神
+var DefaultSchools = []School{SchoolFuYi}
+
+// ParseSchool 解析流派名称，支持中文与拼音，例：扶抑、tiaohou
+func ParseSchool(name string) (School, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case string(SchoolFuYi), "fuyi":
+		return SchoolFuYi, nil
+	case string(SchoolTiaoHou), "tiaohou":
+		return SchoolTiaoHou, nil
+	case string(SchoolTongGuan), "tongguan":
+		return SchoolTongGuan, nil
+	}
+	return "", fmt.Errorf("未知取用流派 %q，可选 扶抑、调候、通关", name)
+}
+
+// 通关要求相战两行按月令调整后的占比均不低于该值
+const tongGuanShare = 30
+
+// UsefulGod 用神、喜神、忌神、仇神及其推导过程
+type UsefulGod struct {
+	School      School          `json:"school" description:"采用的流派"`
+	Useful      GodElement      `json:"useful" description:"用神"`
+	Favorable   GodElement      `json:"favorable" description:"喜神，生用神者"`
+	Unfavorable GodElement      `json:"unfavorable" description:"忌神，克用神者"`
+	Hostile     GodElement      `json:"hostile" description:"仇神，生忌神者"`
+	Steps       []ReasoningStep `json:"steps" description:"推导步骤，按先后排序"`
+}
+
+// GodElement 一个五行及其相对日主的类别
+type GodElement struct {
+	Element  string `json:"element" description:"五行"`
+	Category string `json:"category" description:"相对日主的类别：比劫、印星、食伤、财星、官杀"`
+}
+
+// ReasoningStep 推导用神的一步：依据的规则、观察到的事实与得出的结论
+type ReasoningStep struct {
+	School     School `json:"school,omitempty" description:"所属流派，确定喜忌的步骤为空"`
+	Rule       string `json:"rule" description:"规则"`
+	Finding    string `json:"finding" description:"命局中的事实"`
+	Conclusion string `json:"conclusion" description:"结论"`
+}
+
+// String 返回“火（印星）”形式的文本
+func (g GodElement) String() string {
+	return g.Element + "（" + g.Category + "）"
+}
+
+// DetermineUsefulGod 依次按 schools 中的流派取用神，采用第一个适用的流派；
+// 均不适用时按扶抑取用。八字信息无效时返回 ErrInvalidBazi
+func DetermineUsefulGod(info BaziInfo, schools ...School) (UsefulGod, error) {
+	n, err := parseNatal(info)
+	if err != nil {
+		return UsefulGod{}, err
+	}
+	strength, err := AnalyzeStrength(info)
+	if err != nil {
+		return UsefulGod{}, err
+	}
+	elements, err := AnalyzeElements(info)
+	if err != nil {
+		return UsefulGod{}, err
+	}
+	if len(schools) == 0 {
+		schools = DefaultSchools
+	}
+
+	g := usefulGodFinder{day: StemWuXing(n.dayMaster()), monthBranch: n.branches[MonthPillar], strength: strength, elements: elements}
+	for _, school := range schools {
+		if useful, ok := g.find(school); ok {
+			return g.result(school, useful), nil
+		}
+	}
+	g.step("", "兜底", "所选流派均不适用", "改按扶抑取用")
+	useful, _ := g.find(SchoolFuYi)
+	return g.result(SchoolFuYi, useful), nil
+}
+
+// usefulGodFinder 取用神时的命局信息与已记录的推导步骤
+type usefulGodFinder struct {
+	day         WuXing
+	monthBranch int
+	strength    Strength
+	elements    ElementBalance
+	steps       []ReasoningStep
+}
+
+// step 记录一步推导
+func (g *usefulGodFinder) step(school School, rule, finding, conclusion string) {
+	g.steps = append(g.steps, ReasoningStep{School: school, Rule: rule, Finding: finding, Conclusion: conclusion})
+}
+
+// find 按流派取用神，流派不适用时返回 false
+func (g *usefulGodFinder) find(school School) (WuXing, bool) {
+	switch school {
+	case SchoolTiaoHou:
+		return g.tiaoHou()
+	case SchoolTongGuan:
+		return g.tongGuan()
+	default:
+		return g.fuYi(), true
+	}
+}
+
+// fuYi 扶抑：身强取克泄耗抑之，身弱取生扶扶之
+func (g *usefulGodFinder) fuYi() WuXing {
+	s := g.strength
+	strong := s.Score >= 50
+	side := "身弱宜扶"
+	if strong {
+		side = "身强宜抑"
+	}
+	g.step(SchoolFuYi, "日主强弱", fmt.Sprintf("日主%s%s得分 %s，%s", s.DayMaster, s.Element, FormatFloat(s.Score), s.Level), side)
+
+	var (
+		printWx    = g.category("印星")
+		peerWx     = g.category("比劫")
+		outputWx   = g.category("食伤")
+		wealthWx   = g.category("财星")
+		officialWx = g.category("官杀")
+	)
+	if strong {
+		finding := g.shares(peerWx, printWx)
+		if g.share(printWx) > g.share(peerWx) {
+			g.step(SchoolFuYi, "生扶以印星为主", finding, "印多宜财星破印")
+			return wealthWx
+		}
+		if g.share(officialWx) >= weakShare {
+			g.step(SchoolFuYi, "生扶以比劫为主", finding+"，"+g.shares(officialWx), "比劫多宜官杀制之")
+			return officialWx
+		}
+		g.step(SchoolFuYi, "生扶以比劫为主", finding+"，"+g.shares(officialWx), "官杀无力，取食伤泄秀")
+		return outputWx
+	}
+
+	finding := g.shares(officialWx, outputWx, wealthWx)
+	heaviest := officialWx
+	for _, w := range []WuXing{outputWx, wealthWx} {
+		if g.share(w) > g.share(heaviest) {
+			heaviest = w
+		}
+	}
+	switch heaviest {
+	case officialWx:
+		g.step(SchoolFuYi, "克泄耗中官杀最重", finding, "取印星化杀生身")
+		return printWx
+	case outputWx:
+		g.step(SchoolFuYi, "克泄耗中食伤最重", finding, "取印星制食伤生身")
+		return printWx
+	default:
+		g.step(SchoolFuYi, "克泄耗中财星最重", finding, "取比劫帮身敌财")
+		return peerWx
+	}
+}
+
+// tiaoHou 调候：亥子丑月寒冻取火，巳午未月炎燥取水，春秋气候平和不取
+func (g *usefulGodFinder) tiaoHou() (WuXing, bool) {
+	month := DiZhi[g.monthBranch] + "月"
+	switch g.monthBranch {
+	case 11, 0, 1:
+		g.step(SchoolTiaoHou, "冬生寒冻", "生于"+month+"，"+g.shares(Huo), "取火暖局")
+		return Huo, true
+	case 5, 6, 7:
+		g.step(SchoolTiaoHou, "夏生炎燥", "生于"+month+"，"+g.shares(Shui), "取水润局")
+		return Shui, true
+	}
+	g.step(SchoolTiaoHou, "春秋气候平和", "生于"+month, "无须调候")
+	return 0, false
+}
+
+// tongGuan 通关：相克两行势均力敌时，取克者所生、生被克者的五行化解
+func (g *usefulGodFinder) tongGuan() (WuXing, bool) {
+	best, bestShare := WuXing(-1), 0.0
+	for w := Mu; w <= Shui; w++ {
+		if s := min(g.share(w), g.share(w.Controls())); s >= tongGuanShare && s > bestShare {
+			best, bestShare = w, s
+		}
+	}
+	if best < 0 {
+		g.step(SchoolTongGuan, "两行相战", fmt.Sprintf("没有相克两行的占比均达到 %d%%", tongGuanShare), "无须通关")
+		return 0, false
+	}
+	mediator := best.Generates()
+	g.step(SchoolTongGuan, "两行相战", fmt.Sprintf("%s克%s，%s", best, best.Controls(), g.shares(best, best.Controls())),
+		fmt.Sprintf("取%s通关：%s生%s、%s生%s", mediator, best, mediator, mediator, best.Controls()))
+	return mediator, true
+}
+
+// result 由用神推出喜神、忌神、仇神并记录最后一步
+func (g *usefulGodFinder) result(school School, useful WuXing) UsefulGod {
+	unfavorable := useful.ControlledBy()
+	u := UsefulGod{
+		School:      school,
+		Useful:      g.god(useful),
+		Favorable:   g.god(useful.GeneratedBy()),
+		Unfavorable: g.god(unfavorable),
+		Hostile:     g.god(unfavorable.GeneratedBy()),
+	}
+	g.step("", "喜忌", "生用神者为喜神，克用神者为忌神，生忌神者为仇神",
+		fmt.Sprintf("用神%s，喜神%s，忌神%s，仇神%s", u.Useful, u.Favorable, u.Unfavorable, u.Hostile))
+	u.Steps = g.steps
+	return u
+}
+
+// god 返回五行及其相对日主的类别
+func (g *usefulGodFinder) god(w WuXing) GodElement {
+	return GodElement{Element: w.String(), Category: Category(g.day, w)}
+}
+
+// category 返回相对日主属于指定类别的五行
+func (g *usefulGodFinder) category(name string) WuXing {
+	for w := Mu; w <= Shui; w++ {
+		if Category(g.day, w) == name {
+			return w
+		}
+	}
+	return g.day
+}
+
+// share 返回五行按月令调整后的占比
+func (g *usefulGodFinder) share(w WuXing) float64 {
+	return g.elements.Elements[w].Share
+}
+
+// shares 描述各五行的类别与占比，例：“官杀木占 28.1%”
+func (g *usefulGodFinder) shares(ws ...WuXing) string {
+	parts := make([]string, len(ws))
+	for i, w := range ws {
+		parts[i] = fmt.Sprintf("%s%s占 %s%%", Category(g.day, w), w, FormatFloat(g.share(w)))
+	}
+	return strings.Join(parts, "，")
+}
+
+// Category 返回五行 w 相对日主五行 day 的类别：比劫、印星、食伤、财星、官杀
+func Category(day, w WuXing) string {
+	switch {
+	case w == day:
+		return "比劫"
+	case w.Generates() == day:
+		return "印星"
+	case day.Generates() == w:
+		return "食伤"
+	case day.Controls() == w:
+		return "财星"
+	default:
+		return "官杀"
+	}
+}
+
+// Summary 返回用神喜忌的一句话摘要
+func (u UsefulGod) Summary() string {
+	return fmt.Sprintf("按%s取用：用神%s，喜神%s，忌神%s，仇神%s", u.School, u.Useful, u.Favorable, u.Unfavorable, u.Hostile)
+}
+
+// FormatFloat 格式化得分、占比等数值，省略多余的小数位
+func FormatFloat(x float64) string {
+	return strconv.FormatFloat(x, 'f', -1, 64)
+}
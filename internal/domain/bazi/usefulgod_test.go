@@ -0,0 +1,68 @@
+package bazi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetermineUsefulGod(t *testing.T) {
+	// 己土生于子月偏弱，克泄耗中官杀最重，取印星
+	u, err := DetermineUsefulGod(testBaziInfo())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := UsefulGod{
+		School:      SchoolFuYi,
+		Useful:      GodElement{Element: "火", Category: "印星"},
+		Favorable:   GodElement{Element: "木", Category: "官杀"},
+		Unfavorable: GodElement{Element: "水", Category: "财星"},
+		Hostile:     GodElement{Element: "金", Category: "食伤"},
+	}
+	steps := u.Steps
+	u.Steps = nil
+	if !reflect.DeepEqual(u, want) {
+		t.Errorf("DetermineUsefulGod() = %+v", u)
+	}
+	if len(steps) != 3 || steps[1].Conclusion != "取印星化杀生身" || !strings.Contains(steps[1].Finding, "官杀木占 28.1%") {
+		t.Errorf("推导步骤 = %+v", steps)
+	}
+
+	// 调候不适用时依次尝试下一个流派
+	strong := BaziInfo{Bazi: []string{"甲寅", "丙寅", "甲寅", "甲子"}}
+	u, err = DetermineUsefulGod(strong, SchoolTiaoHou, SchoolTongGuan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.School != SchoolFuYi || u.Useful.Element != "火" || u.Steps[0].Conclusion != "无须调候" || u.Steps[1].Conclusion != "无须通关" || u.Steps[2].Conclusion != "改按扶抑取用" {
+		t.Errorf("DetermineUsefulGod() = %+v", u)
+	}
+
+	// 火金相战取土通关
+	u, err = DetermineUsefulGod(BaziInfo{Bazi: []string{"庚午", "辛巳", "庚辰", "辛巳"}}, SchoolTongGuan)
+	if err != nil || u.School != SchoolTongGuan || u.Useful != (GodElement{Element: "土", Category: "印星"}) {
+		t.Errorf("DetermineUsefulGod() = %+v, %v", u, err)
+	}
+
+	// 冬生取火调候
+	u, err = DetermineUsefulGod(testBaziInfo(), SchoolTiaoHou)
+	if err != nil || u.School != SchoolTiaoHou || u.Useful.Element != "火" || u.Steps[0].Rule != "冬生寒冻" {
+		t.Errorf("DetermineUsefulGod() = %+v, %v", u, err)
+	}
+
+	u, err = DetermineUsefulGod(testBaziInfo())
+	if err != nil || u.Summary() != "按扶抑取用：用神火（印星），喜神木（官杀），忌神水（财星），仇神金（食伤）" {
+		t.Errorf("Summary() = %q, %v", u.Summary(), err)
+	}
+}
+
+func TestParseSchool(t *testing.T) {
+	for name, want := range map[string]School{"扶抑": SchoolFuYi, " TiaoHou ": SchoolTiaoHou, "tongguan": SchoolTongGuan} {
+		if got, err := ParseSchool(name); err != nil || got != want {
+			t.Errorf("ParseSchool(%q) = %q, %v", name, got, err)
+		}
+	}
+	if _, err := ParseSchool("格局"); err == nil {
+		t.Error("未知流派应返回错误")
+	}
+}
@@ -0,0 +1,57 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+)
+
+// DaylightSaving 表示一次夏令时：Start 至 End 期间钟表拨快 1 小时。
+// Start、End 均为北京时间钟表读数：Start 当日 2 时拨至 3 时，End 当日（夏令时）2 时拨回 1 时。
+type DaylightSaving struct {
+	Start time.Time
+	End   time.Time
+}
+
+// String 返回夏令时区间的中文描述
+func (d DaylightSaving) String() string {
+	return fmt.Sprintf("%d年%d月%d日%d时至%d月%d日%d时", d.Start.Year(), d.Start.Month(), d.Start.Day(), d.Start.Hour(), d.End.Month(), d.End.Day(), d.End.Hour())
+}
+
+// chinaDaylightSavings 1986-1991 年全国实行的夏令时：
+// 1986 年自 5 月 4 日起，1987-1991 年自 4 月中旬第一个星期日起，至 9 月中旬第一个星期日止
+var chinaDaylightSavings = []DaylightSaving{
+	{time.Date(1986, 5, 4, 2, 0, 0, 0, beijing), time.Date(1986, 9, 14, 2, 0, 0, 0, beijing)},
+	{time.Date(1987, 4, 12, 2, 0, 0, 0, beijing), time.Date(1987, 9, 13, 2, 0, 0, 0, beijing)},
+	{time.Date(1988, 4, 17, 2, 0, 0, 0, beijing), time.Date(1988, 9, 11, 2, 0, 0, 0, beijing)},
+	{time.Date(1989, 4, 16, 2, 0, 0, 0, beijing), time.Date(1989, 9, 17, 2, 0, 0, 0, beijing)},
+	{time.Date(1990, 4, 15, 2, 0, 0, 0, beijing), time.Date(1990, 9, 16, 2, 0, 0, 0, beijing)},
+	{time.Date(1991, 4, 14, 2, 0, 0, 0, beijing), time.Date(1991, 9, 15, 2, 0, 0, 0, beijing)},
+}
+
+// ClockAmbiguity 表示钟表读数在夏令时切换时的特殊情况
+type ClockAmbiguity int
+
+const (
+	ClockNormal   ClockAmbiguity = iota // 读数唯一
+	ClockSkipped                        // 夏令时开始时被跳过的读数（2 时至 3 时），按夏令时处理
+	ClockRepeated                       // 夏令时结束时重复出现的读数（1 时至 2 时），按夏令时处理
+)
+
+// ChinaDaylightSaving 判断北京时间钟表读数 clock 是否处于 1986-1991 年的夏令时，
+// 返回所在的夏令时区间及读数是否被跳过或重复。clock 只取其年月日时分，忽略时区。
+func ChinaDaylightSaving(clock time.Time) (DaylightSaving, ClockAmbiguity, bool) {
+	wall := time.Date(clock.Year(), clock.Month(), clock.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, beijing)
+	for _, d := range chinaDaylightSavings {
+		if wall.Before(d.Start) || !wall.Before(d.End) {
+			continue
+		}
+		switch {
+		case wall.Before(d.Start.Add(time.Hour)):
+			return d, ClockSkipped, true
+		case !wall.Before(d.End.Add(-time.Hour)):
+			return d, ClockRepeated, true
+		}
+		return d, ClockNormal, true
+	}
+	return DaylightSaving{}, ClockNormal, false
+}
@@ -0,0 +1,55 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChinaDaylightSavingMatchesTZData(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("缺少时区数据: %v", err)
+	}
+	offset := func(year int, month time.Month, day, hour, minute int) int {
+		_, off := time.Date(year, month, day, hour, minute, 0, 0, shanghai).Zone()
+		return off / 3600
+	}
+	for _, d := range chinaDaylightSavings {
+		start, end := d.Start, d.End
+		if offset(start.Year(), start.Month(), start.Day(), 1, 59) != 8 || offset(start.Year(), start.Month(), start.Day(), 3, 0) != 9 {
+			t.Errorf("%s 的开始时间与 tzdata 不一致", d)
+		}
+		if offset(end.Year(), end.Month(), end.Day(), 0, 59) != 9 || offset(end.Year(), end.Month(), end.Day(), 2, 0) != 8 {
+			t.Errorf("%s 的结束时间与 tzdata 不一致", d)
+		}
+	}
+}
+
+func TestChinaDaylightSaving(t *testing.T) {
+	tests := []struct {
+		name      string
+		clock     time.Time
+		want      bool
+		ambiguity ClockAmbiguity
+	}{
+		{"夏令时期间", time.Date(1988, 7, 1, 12, 0, 0, 0, time.UTC), true, ClockNormal},
+		{"开始时被跳过", time.Date(1988, 4, 17, 2, 30, 0, 0, time.UTC), true, ClockSkipped},
+		{"结束时重复", time.Date(1988, 9, 11, 1, 30, 0, 0, time.UTC), true, ClockRepeated},
+		{"结束后", time.Date(1988, 9, 11, 2, 0, 0, 0, time.UTC), false, ClockNormal},
+		{"开始前", time.Date(1986, 5, 4, 1, 59, 0, 0, time.UTC), false, ClockNormal},
+		{"1992年不再实行", time.Date(1992, 7, 1, 12, 0, 0, 0, time.UTC), false, ClockNormal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ambiguity, ok := ChinaDaylightSaving(tt.clock)
+			if ok != tt.want || ambiguity != tt.ambiguity {
+				t.Errorf("ChinaDaylightSaving() = %v, %v, %v, want %v, %v", d, ambiguity, ok, tt.want, tt.ambiguity)
+			}
+		})
+	}
+
+	d, _, _ := ChinaDaylightSaving(time.Date(1988, 7, 1, 12, 0, 0, 0, time.UTC))
+	if got := d.String(); got != "1988年4月17日2时至9月11日2时" {
+		t.Errorf("String() = %s", got)
+	}
+}
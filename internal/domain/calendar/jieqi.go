@@ -0,0 +1,127 @@
+package calendar
+
+import "time"
+
+// 节气计算支持的年份范围
+const (
+	MinYear = 1800
+	MaxYear = 2200
+)
+
+// SolarTerm 表示二十四节气，按公历年内顺序从小寒开始排列
+type SolarTerm int
+
+const (
+	XiaoHan     SolarTerm = iota // 小寒
+	DaHan                        // 大寒
+	LiChun                       // 立春
+	YuShui                       // 雨水
+	JingZhe                      // 惊蛰
+	ChunFen                      // 春分
+	QingMing                     // 清明
+	GuYu                         // 谷雨
+	LiXia                        // 立夏
+	XiaoMan                      // 小满
+	MangZhong                    // 芒种
+	XiaZhi                       // 夏至
+	XiaoShu                      // 小暑
+	DaShu                        // 大暑
+	LiQiu                        // 立秋
+	ChuShu                       // 处暑
+	BaiLu                        // 白露
+	QiuFen                       // 秋分
+	HanLu                        // 寒露
+	ShuangJiang                  // 霜降
+	LiDong                       // 立冬
+	XiaoXue                      // 小雪
+	DaXue                        // 大雪
+	DongZhi                      // 冬至
+)
+
+var solarTermNames = []string{
+	"小寒", "大寒", "立春", "雨水", "惊蛰", "春分",
+	"清明", "谷雨", "立夏", "小满", "芒种", "夏至",
+	"小暑", "大暑", "立秋", "处暑", "白露", "秋分",
+	"寒露", "霜降", "立冬", "小雪", "大雪", "冬至",
+}
+
+// String 返回节气名称
+func (s SolarTerm) String() string {
+	if s < XiaoHan || s > DongZhi {
+		return ""
+	}
+	return solarTermNames[s]
+}
+
+// Longitude 返回节气对应的太阳视黄经（度）
+func (s SolarTerm) Longitude() float64 {
+	return normalizeDegrees(285 + 15*float64(s))
+}
+
+// IsJie 判断是否为“节”（月令交接点），否则为“中气”
+func (s SolarTerm) IsJie() bool {
+	return s%2 == 0
+}
+
+// TermInstant 表示某个节气的交节时刻
+type TermInstant struct {
+	Term SolarTerm
+	Time time.Time // 交节时刻（UTC）
+}
+
+// SolarTermTime 计算公历 year 年指定节气的交节时刻（UTC）
+func SolarTermTime(year int, term SolarTerm) time.Time {
+	longitude := term.Longitude()
+	guess := JulianDay(time.Date(year, 3, 20, 0, 0, 0, 0, time.UTC))
+	if term < ChunFen {
+		// 小寒至惊蛰位于春分之前
+		guess -= (360 - longitude) / 360 * tropicalYear
+	} else {
+		guess += longitude / 360 * tropicalYear
+	}
+	return FromJulianDay(findLongitude(longitude, guess))
+}
+
+// SolarTermsOfYear 返回公历 year 年的全部二十四节气，按时间排序
+func SolarTermsOfYear(year int) []TermInstant {
+	terms := make([]TermInstant, 0, 24)
+	for term := XiaoHan; term <= DongZhi; term++ {
+		terms = append(terms, TermInstant{Term: term, Time: SolarTermTime(year, term)})
+	}
+	return terms
+}
+
+// PrevJie 返回 t 时刻之前（含）最近的一个“节”
+func PrevJie(t time.Time) TermInstant {
+	term := jieAt(SolarLongitude(t))
+	instant := termNear(term, t)
+	if instant.After(t) {
+		// 迭代误差导致越界时回退一个节
+		term = (term + 22) % 24
+		instant = termNear(term, t)
+	}
+	return TermInstant{Term: term, Time: instant}
+}
+
+// NextJie 返回 t 时刻之后最近的一个“节”
+func NextJie(t time.Time) TermInstant {
+	term := (jieAt(SolarLongitude(t)) + 2) % 24
+	instant := termNear(term, t)
+	if !instant.After(t) {
+		term = (term + 2) % 24
+		instant = termNear(term, t)
+	}
+	return TermInstant{Term: term, Time: instant}
+}
+
+// jieAt 返回太阳视黄经所处月令的起始“节”
+func jieAt(longitude float64) SolarTerm {
+	return SolarTerm(int(normalizeDegrees(longitude-285)/30) * 2)
+}
+
+// termNear 求 t 附近（前后半年内）指定节气的交节时刻
+func termNear(term SolarTerm, t time.Time) time.Time {
+	jd := JulianDay(t)
+	diff := normalizeDegrees(term.Longitude()-SolarLongitude(t)+180) - 180
+	return FromJulianDay(findLongitude(term.Longitude(), jd+diff*tropicalYear/360))
+}
@@ -0,0 +1,76 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSolarTermTime(t *testing.T) {
+	// 对照中国科学院紫金山天文台及 USNO 公布的交节时刻（UTC）
+	tests := []struct {
+		name string
+		year int
+		term SolarTerm
+		want time.Time
+	}{
+		{"2000年春分", 2000, ChunFen, time.Date(2000, 3, 20, 7, 35, 0, 0, time.UTC)},
+		{"2000年夏至", 2000, XiaZhi, time.Date(2000, 6, 21, 1, 48, 0, 0, time.UTC)},
+		{"2000年秋分", 2000, QiuFen, time.Date(2000, 9, 22, 17, 28, 0, 0, time.UTC)},
+		{"2000年冬至", 2000, DongZhi, time.Date(2000, 12, 21, 13, 37, 0, 0, time.UTC)},
+		{"2023年冬至", 2023, DongZhi, time.Date(2023, 12, 22, 3, 27, 0, 0, time.UTC)},
+		{"2024年立春", 2024, LiChun, time.Date(2024, 2, 4, 8, 27, 0, 0, time.UTC)},
+		{"2024年春分", 2024, ChunFen, time.Date(2024, 3, 20, 3, 6, 0, 0, time.UTC)},
+		{"2024年夏至", 2024, XiaZhi, time.Date(2024, 6, 20, 20, 51, 0, 0, time.UTC)},
+		{"2024年冬至", 2024, DongZhi, time.Date(2024, 12, 21, 9, 21, 0, 0, time.UTC)},
+		{"2025年立春", 2025, LiChun, time.Date(2025, 2, 3, 14, 10, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SolarTermTime(tt.year, tt.term)
+			if diff := got.Sub(tt.want); diff > 2*time.Minute || diff < -2*time.Minute {
+				t.Errorf("SolarTermTime(%d, %s) = %v, want %v", tt.year, tt.term, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolarTermsOfYear(t *testing.T) {
+	for _, year := range []int{MinYear, 1900, 2000, 2100, MaxYear} {
+		terms := SolarTermsOfYear(year)
+		if len(terms) != 24 {
+			t.Fatalf("SolarTermsOfYear(%d) 返回 %d 个节气", year, len(terms))
+		}
+		for i, term := range terms {
+			if term.Time.Year() != year {
+				t.Errorf("%d年%s 落在 %v", year, term.Term, term.Time)
+			}
+			if i > 0 && !term.Time.After(terms[i-1].Time) {
+				t.Errorf("%d年节气未按时间排序: %s %v", year, term.Term, term.Time)
+			}
+		}
+	}
+}
+
+func TestPrevNextJie(t *testing.T) {
+	birth := time.Date(2000, 1, 1, 19, 4, 0, 0, time.UTC)
+
+	prev := PrevJie(birth)
+	if prev.Term != DaXue || prev.Time.After(birth) || prev.Time.Year() != 1999 {
+		t.Errorf("PrevJie() = %s %v, want 1999年大雪", prev.Term, prev.Time)
+	}
+
+	next := NextJie(birth)
+	if next.Term != XiaoHan || !next.Time.After(birth) {
+		t.Errorf("NextJie() = %s %v, want 2000年小寒", next.Term, next.Time)
+	}
+}
+
+func TestSolarTerm(t *testing.T) {
+	if LiChun.String() != "立春" || LiChun.Longitude() != 315 || !LiChun.IsJie() {
+		t.Errorf("立春属性错误: %s %v %v", LiChun, LiChun.Longitude(), LiChun.IsJie())
+	}
+	if ChunFen.Longitude() != 0 || ChunFen.IsJie() {
+		t.Errorf("春分属性错误: %v %v", ChunFen.Longitude(), ChunFen.IsJie())
+	}
+}
@@ -0,0 +1,219 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ErrInvalidDate 表示公历或农历日期不存在
+var ErrInvalidDate = errors.New("无效的日期")
+
+// unixEpochJDN 1970-01-01 对应的儒略日数
+const unixEpochJDN = 2440588
+
+var (
+	lunarMonthNames = []string{"正月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"}
+	lunarDayTens    = []string{"初", "十", "廿", "三"}
+	lunarDayUnits   = []string{"十", "一", "二", "三", "四", "五", "六", "七", "八", "九"}
+)
+
+// LunarDate 表示农历日期值对象
+type LunarDate struct {
+	Year  int  `json:"year"`  // 农历年（以正月初一为岁首，用公历年份编号）
+	Month int  `json:"month"` // 农历月 1-12
+	Day   int  `json:"day"`   // 农历日 1-30
+	Leap  bool `json:"leap"`  // 是否闰月
+}
+
+// MonthName 返回农历月名称，如“闰二月”
+func (d LunarDate) MonthName() string {
+	name := lunarMonthNames[d.Month-1]
+	if d.Leap {
+		return "闰" + name
+	}
+	return name
+}
+
+// DayName 返回农历日名称，如“初一”“廿六”
+func (d LunarDate) DayName() string {
+	switch d.Day {
+	case 10:
+		return "初十"
+	case 20:
+		return "二十"
+	case 30:
+		return "三十"
+	}
+	return lunarDayTens[d.Day/10] + lunarDayUnits[d.Day%10]
+}
+
+// String 返回农历日期的中文表示，如“2023年闰二月初一”
+func (d LunarDate) String() string {
+	return fmt.Sprintf("%d年%s%s", d.Year, d.MonthName(), d.DayName())
+}
+
+// lunarMonth 表示一个农历月
+type lunarMonth struct {
+	year  int  // 所属农历年
+	month int  // 月序 1-12
+	leap  bool // 是否闰月
+	start int  // 初一的儒略日数（北京时间）
+	days  int  // 当月天数
+}
+
+// SolarToLunar 将公历日期（北京时间）转换为农历日期
+func SolarToLunar(year, month, day int) (LunarDate, error) {
+	if !validSolarDate(year, month, day) {
+		return LunarDate{}, fmt.Errorf("%w: 公历%d年%d月%d日", ErrInvalidDate, year, month, day)
+	}
+	if year < MinYear || year > MaxYear {
+		return LunarDate{}, fmt.Errorf("%w: 年份超出支持范围（%d-%d）", ErrInvalidDate, MinYear, MaxYear)
+	}
+
+	target := dayNumber(year, month, day)
+	for _, sui := range []int{year, year + 1} {
+		for _, m := range monthsOfSui(sui) {
+			if target >= m.start && target < m.start+m.days {
+				return LunarDate{Year: m.year, Month: m.month, Day: target - m.start + 1, Leap: m.leap}, nil
+			}
+		}
+	}
+	return LunarDate{}, fmt.Errorf("%w: 公历%d年%d月%d日超出支持范围", ErrInvalidDate, year, month, day)
+}
+
+// LunarToSolar 将农历日期转换为公历日期（北京时间 0 点）
+func LunarToSolar(year, month, day int, leap bool) (time.Time, error) {
+	m, err := findLunarMonth(year, month, leap)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if day < 1 || day > m.days {
+		return time.Time{}, fmt.Errorf("%w: 农历%d年%s只有%d天", ErrInvalidDate, year, LunarDate{Month: month, Leap: leap}.MonthName(), m.days)
+	}
+	return dateOfDayNumber(m.start + day - 1), nil
+}
+
+// LeapMonth 返回农历 year 年的闰月月序，无闰月时返回 0
+func LeapMonth(year int) int {
+	for _, sui := range []int{year, year + 1} {
+		for _, m := range monthsOfSui(sui) {
+			if m.year == year && m.leap {
+				return m.month
+			}
+		}
+	}
+	return 0
+}
+
+// MonthDays 返回农历月的天数（大月 30 天，小月 29 天）
+func MonthDays(year, month int, leap bool) (int, error) {
+	m, err := findLunarMonth(year, month, leap)
+	if err != nil {
+		return 0, err
+	}
+	return m.days, nil
+}
+
+// findLunarMonth 在农历 year 年中查找指定月份
+func findLunarMonth(year, month int, leap bool) (lunarMonth, error) {
+	if month < 1 || month > 12 {
+		return lunarMonth{}, fmt.Errorf("%w: 农历月份应为 1-12，实际为 %d", ErrInvalidDate, month)
+	}
+	if year < MinYear || year > MaxYear {
+		return lunarMonth{}, fmt.Errorf("%w: 年份超出支持范围（%d-%d）", ErrInvalidDate, MinYear, MaxYear)
+	}
+	for _, sui := range []int{year, year + 1} {
+		for _, m := range monthsOfSui(sui) {
+			if m.year == year && m.month == month && m.leap == leap {
+				return m, nil
+			}
+		}
+	}
+	return lunarMonth{}, fmt.Errorf("%w: 农历%d年没有%s", ErrInvalidDate, year, LunarDate{Month: month, Leap: leap}.MonthName())
+}
+
+// monthsOfSui 计算一岁（上一年冬至所在月至本年冬至所在月之前）的全部农历月。
+// 按定朔定气规则：冬至所在月为十一月，岁中有十三个月时，第一个不含中气的月为闰月。
+// 历法日期统一按北京时间（UTC+8）划分。
+func monthsOfSui(year int) []lunarMonth {
+	k := newMoonOnOrBefore(beijingDay(JulianDay(SolarTermTime(year-1, DongZhi))))
+	kEnd := newMoonOnOrBefore(beijingDay(JulianDay(SolarTermTime(year, DongZhi))))
+	count := int(kEnd - k)
+
+	var zhongqi []int
+	for _, y := range []int{year - 1, year} {
+		for _, term := range SolarTermsOfYear(y) {
+			if !term.Term.IsJie() {
+				zhongqi = append(zhongqi, beijingDay(JulianDay(term.Time)))
+			}
+		}
+	}
+	hasZhongqi := func(start, end int) bool {
+		for _, d := range zhongqi {
+			if d >= start && d < end {
+				return true
+			}
+		}
+		return false
+	}
+
+	months := make([]lunarMonth, 0, count)
+	start := beijingDay(newMoon(k))
+	number, lunarYear, leapFound := 11, year-1, false
+	for i := range count {
+		end := beijingDay(newMoon(k + float64(i+1)))
+		leap := false
+		if i > 0 {
+			if count == 13 && !leapFound && !hasZhongqi(start, end) {
+				leap, leapFound = true, true
+			} else {
+				number = number%12 + 1
+				if number == 1 {
+					lunarYear = year
+				}
+			}
+		}
+		months = append(months, lunarMonth{year: lunarYear, month: number, leap: leap, start: start, days: end - start})
+		start = end
+	}
+	return months
+}
+
+// newMoonOnOrBefore 返回北京时间 day 当天或之前最近一次朔的序号
+func newMoonOnOrBefore(day int) float64 {
+	k := newMoonIndex(float64(day))
+	for beijingDay(newMoon(k)) > day {
+		k--
+	}
+	for beijingDay(newMoon(k+1)) <= day {
+		k++
+	}
+	return k
+}
+
+// beijingDay 返回世界时儒略日所在北京时间日期的儒略日数
+func beijingDay(jd float64) int {
+	return int(math.Floor(jd + 0.5 + 8.0/24))
+}
+
+// dayNumber 返回公历日期的儒略日数
+func dayNumber(year, month, day int) int {
+	return int(time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).Unix()/86400) + unixEpochJDN
+}
+
+// dateOfDayNumber 将儒略日数转换为北京时间 0 点
+func dateOfDayNumber(day int) time.Time {
+	date := time.Unix(int64(day-unixEpochJDN)*86400, 0).UTC()
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, beijing)
+}
+
+// validSolarDate 校验公历日期是否存在
+func validSolarDate(year, month, day int) bool {
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	return date.Year() == year && int(date.Month()) == month && date.Day() == day
+}
+
+// beijing 北京时间时区（UTC+8）
+var beijing = time.FixedZone("CST", 8*3600)
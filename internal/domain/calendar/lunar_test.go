@@ -0,0 +1,111 @@
+package calendar
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSolarToLunar(t *testing.T) {
+	tests := []struct {
+		name             string
+		year, month, day int
+		want             LunarDate
+	}{
+		{"己卯年十一月", 2000, 1, 2, LunarDate{Year: 1999, Month: 11, Day: 26}},
+		{"2000年春节", 2000, 2, 5, LunarDate{Year: 2000, Month: 1, Day: 1}},
+		{"1985年春节", 1985, 2, 20, LunarDate{Year: 1985, Month: 1, Day: 1}},
+		{"2024年春节", 2024, 2, 10, LunarDate{Year: 2024, Month: 1, Day: 1}},
+		{"2024年除夕", 2024, 2, 9, LunarDate{Year: 2023, Month: 12, Day: 30}},
+		{"2017年闰六月", 2017, 7, 23, LunarDate{Year: 2017, Month: 6, Day: 1, Leap: true}},
+		{"2020年闰四月", 2020, 5, 23, LunarDate{Year: 2020, Month: 4, Day: 1, Leap: true}},
+		{"2023年闰二月", 2023, 3, 22, LunarDate{Year: 2023, Month: 2, Day: 1, Leap: true}},
+		{"2033年闰十一月", 2033, 12, 22, LunarDate{Year: 2033, Month: 11, Day: 1, Leap: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SolarToLunar(tt.year, tt.month, tt.day)
+			if err != nil {
+				t.Fatalf("SolarToLunar() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SolarToLunar(%d, %d, %d) = %v, want %v", tt.year, tt.month, tt.day, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLunarToSolar(t *testing.T) {
+	tests := []struct {
+		name    string
+		date    LunarDate
+		want    time.Time
+		wantErr bool
+	}{
+		{"闰二月初一", LunarDate{Year: 2023, Month: 2, Day: 1, Leap: true}, time.Date(2023, 3, 22, 0, 0, 0, 0, beijing), false},
+		{"二月初一", LunarDate{Year: 2023, Month: 2, Day: 1}, time.Date(2023, 2, 20, 0, 0, 0, 0, beijing), false},
+		{"十一月廿六", LunarDate{Year: 1999, Month: 11, Day: 26}, time.Date(2000, 1, 2, 0, 0, 0, 0, beijing), false},
+		{"不存在的闰月", LunarDate{Year: 2024, Month: 2, Day: 1, Leap: true}, time.Time{}, true},
+		{"小月三十", LunarDate{Year: 2023, Month: 2, Day: 30, Leap: true}, time.Time{}, true},
+		{"无效月份", LunarDate{Year: 2023, Month: 13, Day: 1}, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LunarToSolar(tt.date.Year, tt.date.Month, tt.date.Day, tt.date.Leap)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidDate) {
+					t.Errorf("LunarToSolar() error = %v, want ErrInvalidDate", err)
+				}
+				return
+			}
+			if err != nil || !got.Equal(tt.want) {
+				t.Errorf("LunarToSolar() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeapMonth(t *testing.T) {
+	tests := map[int]int{2017: 6, 2020: 4, 2023: 2, 2024: 0, 2025: 6}
+	for year, want := range tests {
+		if got := LeapMonth(year); got != want {
+			t.Errorf("LeapMonth(%d) = %d, want %d", year, got, want)
+		}
+	}
+}
+
+func TestLunarDateString(t *testing.T) {
+	tests := []struct {
+		date LunarDate
+		want string
+	}{
+		{LunarDate{Year: 1999, Month: 11, Day: 26}, "1999年十一月廿六"},
+		{LunarDate{Year: 2023, Month: 2, Day: 1, Leap: true}, "2023年闰二月初一"},
+		{LunarDate{Year: 2024, Month: 1, Day: 10}, "2024年正月初十"},
+		{LunarDate{Year: 2024, Month: 12, Day: 20}, "2024年十二月二十"},
+	}
+	for _, tt := range tests {
+		if got := tt.date.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	start := time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+	for d := start; d.Year() < 1902; d = d.AddDate(0, 0, 1) {
+		lunar, err := SolarToLunar(d.Year(), int(d.Month()), d.Day())
+		if err != nil {
+			t.Fatalf("SolarToLunar(%s) error = %v", d.Format("2006-01-02"), err)
+		}
+		solar, err := LunarToSolar(lunar.Year, lunar.Month, lunar.Day, lunar.Leap)
+		if err != nil {
+			t.Fatalf("LunarToSolar(%v) error = %v", lunar, err)
+		}
+		if solar.Format("2006-01-02") != d.Format("2006-01-02") {
+			t.Fatalf("往返转换 %s -> %v -> %s", d.Format("2006-01-02"), lunar, solar.Format("2006-01-02"))
+		}
+	}
+}
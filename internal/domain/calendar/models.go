@@ -0,0 +1,16 @@
+package calendar
+
+// 历法转换方向
+const (
+	LunarToSolarType = 0 // 农历转公历
+	SolarToLunarType = 1 // 公历转农历
+)
+
+// ConvertRequest 定义了历法转换工具的输入参数结构。
+type ConvertRequest struct {
+	Type  int  `json:"type" description:"转换方向 0:农历转公历 1:公历转农历（整数）" required:"true" enum:"0,1"`
+	Year  int  `json:"year" description:"年 例: 2023（整数）" required:"true"`
+	Month int  `json:"month" description:"月 例: 2（整数）" required:"true"`
+	Day   int  `json:"day" description:"日 例: 1（整数）" required:"true"`
+	Leap  bool `json:"leap,omitempty" description:"农历月是否为闰月，仅 type=0 时有效 例: true（布尔）" default:"false"`
+}
@@ -0,0 +1,91 @@
+package calendar
+
+import "math"
+
+// 朔望月平均长度（日）
+const synodicMonth = 29.530588861
+
+// newMoonTerm 表示朔日修正项：系数 × E^power × sin(Σ乘数·角)
+type newMoonTerm struct {
+	coef         float64
+	ePower       int
+	m, mp, f, om float64 // 太阳平近点角、月亮平近点角、月亮纬度参数、升交点黄经的乘数
+}
+
+// 朔日周期项（Meeus《天文算法》第 49 章）
+var newMoonTerms = []newMoonTerm{
+	{-0.40720, 0, 0, 1, 0, 0},
+	{0.17241, 1, 1, 0, 0, 0},
+	{0.01608, 0, 0, 2, 0, 0},
+	{0.01039, 0, 0, 0, 2, 0},
+	{0.00739, 1, -1, 1, 0, 0},
+	{-0.00514, 1, 1, 1, 0, 0},
+	{0.00208, 2, 2, 0, 0, 0},
+	{-0.00111, 0, 0, 1, -2, 0},
+	{-0.00057, 0, 0, 1, 2, 0},
+	{0.00056, 1, 1, 2, 0, 0},
+	{-0.00042, 0, 0, 3, 0, 0},
+	{0.00042, 1, 1, 0, 2, 0},
+	{0.00038, 1, 1, 0, -2, 0},
+	{-0.00024, 1, -1, 2, 0, 0},
+	{-0.00017, 0, 0, 0, 0, 1},
+	{-0.00007, 0, 2, 1, 0, 0},
+	{0.00004, 0, 0, 2, -2, 0},
+	{0.00004, 0, 3, 0, 0, 0},
+	{0.00003, 0, 1, 1, -2, 0},
+	{0.00003, 0, 0, 2, 2, 0},
+	{-0.00003, 0, 1, 1, 2, 0},
+	{0.00003, 0, -1, 1, 2, 0},
+	{-0.00002, 0, -1, 1, -2, 0},
+	{-0.00002, 0, 1, 3, 0, 0},
+	{0.00002, 0, 0, 4, 0, 0},
+}
+
+// 行星摄动附加项：系数、常数项、k 的系数
+var planetaryTerms = [][3]float64{
+	{0.000325, 299.77, 0.107408},
+	{0.000165, 251.88, 0.016321},
+	{0.000164, 251.83, 26.651886},
+	{0.000126, 349.42, 36.412478},
+	{0.000110, 84.66, 18.206239},
+	{0.000062, 141.74, 53.303771},
+	{0.000060, 207.14, 2.453732},
+	{0.000056, 154.84, 7.306860},
+	{0.000047, 34.52, 27.261239},
+	{0.000042, 207.19, 0.121824},
+	{0.000040, 291.34, 1.844379},
+	{0.000037, 161.72, 24.198154},
+	{0.000035, 239.56, 25.513099},
+	{0.000023, 331.55, 3.592518},
+}
+
+// newMoon 计算第 k 个朔（k=0 为 2000 年 1 月 6 日的朔）的时刻，返回世界时儒略日
+func newMoon(k float64) float64 {
+	t := k / 1236.85
+	t2, t3, t4 := t*t, t*t*t, t*t*t*t
+
+	jde := 2451550.09766 + synodicMonth*k + 0.00015437*t2 - 0.000000150*t3 + 0.00000000073*t4
+	e := 1 - 0.002516*t - 0.0000074*t2
+	m := toRadians(2.5534 + 29.10535670*k - 0.0000014*t2 - 0.00000011*t3)
+	mp := toRadians(201.5643 + 385.81693528*k + 0.0107582*t2 + 0.00001238*t3 - 0.000000058*t4)
+	f := toRadians(160.7108 + 390.67050284*k - 0.0016118*t2 - 0.00000227*t3 + 0.000000011*t4)
+	om := toRadians(124.7746 - 1.56375588*k + 0.0020672*t2 + 0.00000215*t3)
+
+	for _, term := range newMoonTerms {
+		jde += term.coef * math.Pow(e, float64(term.ePower)) *
+			math.Sin(term.m*m+term.mp*mp+term.f*f+term.om*om)
+	}
+
+	planetary := planetaryTerms[0][0] * math.Sin(toRadians(planetaryTerms[0][1]+planetaryTerms[0][2]*k-0.009173*t2))
+	for _, term := range planetaryTerms[1:] {
+		planetary += term[0] * math.Sin(toRadians(term[1]+term[2]*k))
+	}
+	jde += planetary
+
+	return jde - DeltaT(decimalYear(jde))/86400
+}
+
+// newMoonIndex 估算世界时儒略日 jd 之前（含）最近一次朔的序号
+func newMoonIndex(jd float64) float64 {
+	return math.Floor((jd - 2451550.09766) / synodicMonth)
+}
@@ -0,0 +1,155 @@
+package calendar
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	j2000        = 2451545.0  // J2000.0 儒略日
+	tropicalYear = 365.242189 // 回归年长度（日）
+	unixEpochJD  = 2440587.5  // 1970-01-01T00:00:00Z 对应的儒略日
+)
+
+// JulianDay 将时间转换为儒略日（世界时）
+func JulianDay(t time.Time) float64 {
+	return float64(t.UnixNano())/float64(24*time.Hour) + unixEpochJD
+}
+
+// FromJulianDay 将儒略日（世界时）转换为 UTC 时间，精确到秒
+func FromJulianDay(jd float64) time.Time {
+	seconds := math.Round((jd - unixEpochJD) * 86400)
+	return time.Unix(int64(seconds), 0).UTC()
+}
+
+// DeltaT 返回力学时与世界时之差 ΔT（秒），采用 Espenak-Meeus 多项式，适用于 1800-2200 年
+func DeltaT(year float64) float64 {
+	switch {
+	case year < 1800:
+		u := (year - 1820) / 100
+		return -20 + 32*u*u
+	case year < 1860:
+		t := year - 1800
+		return 13.72 - 0.332447*t + 0.0068612*t*t + 0.0041116*math.Pow(t, 3) -
+			0.00037436*math.Pow(t, 4) + 0.0000121272*math.Pow(t, 5) -
+			0.0000001699*math.Pow(t, 6) + 0.000000000875*math.Pow(t, 7)
+	case year < 1900:
+		t := year - 1860
+		return 7.62 + 0.5737*t - 0.251754*t*t + 0.01680668*math.Pow(t, 3) -
+			0.0004473624*math.Pow(t, 4) + math.Pow(t, 5)/233174
+	case year < 1920:
+		t := year - 1900
+		return -2.79 + 1.494119*t - 0.0598939*t*t + 0.0061966*math.Pow(t, 3) - 0.000197*math.Pow(t, 4)
+	case year < 1941:
+		t := year - 1920
+		return 21.20 + 0.84493*t - 0.076100*t*t + 0.0020936*math.Pow(t, 3)
+	case year < 1961:
+		t := year - 1950
+		return 29.07 + 0.407*t - t*t/233 + math.Pow(t, 3)/2547
+	case year < 1986:
+		t := year - 1975
+		return 45.45 + 1.067*t - t*t/260 - math.Pow(t, 3)/718
+	case year < 2005:
+		t := year - 2000
+		return 63.86 + 0.3345*t - 0.060374*t*t + 0.0017275*math.Pow(t, 3) +
+			0.000651814*math.Pow(t, 4) + 0.00002373599*math.Pow(t, 5)
+	case year < 2050:
+		t := year - 2000
+		return 62.92 + 0.32217*t + 0.005589*t*t
+	case year < 2150:
+		u := (year - 1820) / 100
+		return -20 + 32*u*u - 0.5628*(2150-year)
+	default:
+		u := (year - 1820) / 100
+		return -20 + 32*u*u
+	}
+}
+
+// decimalYear 返回儒略日对应的小数年份，用于 ΔT 估算
+func decimalYear(jd float64) float64 {
+	return 2000 + (jd-j2000)/365.25
+}
+
+// SolarLongitude 返回给定时刻的太阳地心视黄经（度，0-360）
+func SolarLongitude(t time.Time) float64 {
+	return apparentLongitude(julianEphemerisDay(JulianDay(t)))
+}
+
+// julianEphemerisDay 将世界时儒略日转换为力学时儒略日
+func julianEphemerisDay(jd float64) float64 {
+	return jd + DeltaT(decimalYear(jd))/86400
+}
+
+// apparentLongitude 以 VSOP87 截断级数计算力学时 jde 的太阳视黄经（度）
+func apparentLongitude(jde float64) float64 {
+	tau := (jde - j2000) / 365250
+	t := tau * 10
+
+	l := sumSeries(earthL, tau)
+	r := sumSeries(earthR, tau)
+
+	// 日心黄经转换为地心黄经并归算到 FK5 系统
+	theta := toDegrees(l) + 180
+	theta += -0.09033 / 3600
+
+	// 章动与光行差
+	nutation, _ := nutation(t)
+	aberration := -20.4898 / r
+
+	return normalizeDegrees(theta + (nutation+aberration)/3600)
+}
+
+// nutation 以 Meeus 简化式计算黄经章动与交角章动（角秒），t 为 J2000 起算的儒略世纪数
+func nutation(t float64) (longitude, obliquity float64) {
+	omega := toRadians(125.04452 - 1934.136261*t)
+	sunMean := toRadians(280.4665 + 36000.7698*t)
+	moonMean := toRadians(218.3165 + 481267.8813*t)
+	longitude = -17.20*math.Sin(omega) - 1.32*math.Sin(2*sunMean) -
+		0.23*math.Sin(2*moonMean) + 0.21*math.Sin(2*omega)
+	obliquity = 9.20*math.Cos(omega) + 0.57*math.Cos(2*sunMean) +
+		0.10*math.Cos(2*moonMean) - 0.09*math.Cos(2*omega)
+	return longitude, obliquity
+}
+
+// sumSeries 计算 VSOP87 级数 Σ(Xn·τⁿ)，结果单位为弧度或天文单位
+func sumSeries(series [][]vsopTerm, tau float64) float64 {
+	var sum, power float64 = 0, 1
+	for _, terms := range series {
+		var s float64
+		for _, term := range terms {
+			s += term.a * math.Cos(term.b+term.c*tau)
+		}
+		sum += s * power
+		power *= tau
+	}
+	return sum / 1e8
+}
+
+// findLongitude 从 guess（世界时儒略日）附近迭代求太阳视黄经等于 target 的时刻
+func findLongitude(target, guess float64) float64 {
+	jde := julianEphemerisDay(guess)
+	for range 20 {
+		diff := normalizeDegrees(target-apparentLongitude(jde)+180) - 180
+		jde += diff * tropicalYear / 360
+		if math.Abs(diff) < 1e-7 {
+			break
+		}
+	}
+	return jde - DeltaT(decimalYear(jde))/86400
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func toDegrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+func normalizeDegrees(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
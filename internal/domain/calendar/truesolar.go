@@ -0,0 +1,56 @@
+package calendar
+
+import (
+	"math"
+	"time"
+)
+
+// TrueSolarTime 表示一次真太阳时校正：真太阳时 = 钟表时间 + 经度时差 + 均时差
+type TrueSolarTime struct {
+	Civil            time.Time // 校正前的钟表时间（区时）
+	Corrected        time.Time // 校正后的真太阳时，与 Civil 处于同一时区
+	Longitude        float64   // 出生地经度（东经为正）
+	Meridian         float64   // 时区中央经线经度
+	LongitudeSeconds int       // 经度时差（秒），每偏离中央经线 1 度相差 4 分钟
+	EquationSeconds  int       // 均时差（秒），真太阳时与平太阳时之差
+}
+
+// CorrectionSeconds 返回总校正量（秒），正值表示真太阳时晚于钟表时间
+func (t TrueSolarTime) CorrectionSeconds() int {
+	return t.LongitudeSeconds + t.EquationSeconds
+}
+
+// NewTrueSolarTime 根据钟表时间及出生地经度计算真太阳时，中央经线由 civil 所在时区的 UTC 偏移推出
+func NewTrueSolarTime(civil time.Time, longitude float64) TrueSolarTime {
+	_, offset := civil.Zone()
+	meridian := float64(offset) / 3600 * 15
+
+	result := TrueSolarTime{
+		Civil:            civil,
+		Longitude:        longitude,
+		Meridian:         meridian,
+		LongitudeSeconds: int(math.Round((longitude - meridian) * 240)),
+		EquationSeconds:  int(math.Round(EquationOfTime(civil))),
+	}
+	result.Corrected = civil.Add(time.Duration(result.CorrectionSeconds()) * time.Second)
+	return result
+}
+
+// EquationOfTime 返回 t 时刻的均时差（秒），按 Meeus《天文算法》第 28 章由太阳平黄经与视赤经之差求得
+func EquationOfTime(t time.Time) float64 {
+	jde := julianEphemerisDay(JulianDay(t))
+	tau := (jde - j2000) / 365250
+	centuries := tau * 10
+
+	meanLongitude := 280.4664567 + 360007.6982779*tau + 0.03032028*tau*tau +
+		tau*tau*tau/49931 - tau*tau*tau*tau/15300 - tau*tau*tau*tau*tau/2000000
+
+	deltaPsi, deltaEpsilon := nutation(centuries)
+	obliquity := toRadians(23.43929111 - 0.0130041667*centuries + deltaEpsilon/3600)
+	lambda := toRadians(apparentLongitude(jde))
+	rightAscension := toDegrees(math.Atan2(math.Cos(obliquity)*math.Sin(lambda), math.Cos(lambda)))
+
+	e := meanLongitude - 0.0057183 - rightAscension + deltaPsi/3600*math.Cos(obliquity)
+	e = normalizeDegrees(e+180) - 180
+	return e * 240
+}
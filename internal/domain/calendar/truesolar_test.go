@@ -0,0 +1,47 @@
+package calendar
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEquationOfTime(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		want float64 // 秒
+	}{
+		// Meeus 例 28.b：1992年10月13日0时力学时，E = 13分42.6秒
+		{"Meeus 例题", time.Date(1992, 10, 12, 23, 59, 0, 0, time.UTC), 822.6},
+		{"二月极小值", time.Date(2024, 2, 11, 12, 0, 0, 0, time.UTC), -852},
+		{"十一月极大值", time.Date(2024, 11, 3, 12, 0, 0, 0, time.UTC), 985},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EquationOfTime(tt.t); math.Abs(got-tt.want) > 3 {
+				t.Errorf("EquationOfTime() = %.1f, want %.1f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTrueSolarTime(t *testing.T) {
+	// 乌鲁木齐（东经87.62度）2024年2月11日12时，经度时差约 -2时9分31秒，均时差约 -14分12秒
+	civil := time.Date(2024, 2, 11, 12, 0, 0, 0, beijing)
+	got := NewTrueSolarTime(civil, 87.62)
+
+	if got.Meridian != 120 {
+		t.Errorf("Meridian = %v, want 120", got.Meridian)
+	}
+	if got.LongitudeSeconds != -7771 {
+		t.Errorf("LongitudeSeconds = %d, want -7771", got.LongitudeSeconds)
+	}
+	if math.Abs(float64(got.EquationSeconds+852)) > 3 {
+		t.Errorf("EquationSeconds = %d, want about -852", got.EquationSeconds)
+	}
+	want := civil.Add(time.Duration(got.CorrectionSeconds()) * time.Second)
+	if !got.Corrected.Equal(want) || got.Corrected.Hour() != 9 {
+		t.Errorf("Corrected = %v, want %v", got.Corrected, want)
+	}
+}
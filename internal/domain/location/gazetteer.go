@@ -0,0 +1,297 @@
+package location
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Place 表示地名录中的一个地点
+type Place struct {
+	Province    string  `json:"province"`              // 省级行政区名称
+	City        string  `json:"city"`                  // 市县名称
+	Code        string  `json:"code"`                  // 县级行政区划代码（GB/T 2260），已撤并的地名取今所属县级行政区
+	Longitude   float64 `json:"longitude"`             // 经度，东经为正
+	Latitude    float64 `json:"latitude"`              // 纬度，北纬为正
+	Approximate bool    `json:"approximate,omitempty"` // 坐标未收录，以省会坐标近似
+}
+
+// AliasKind 表示别名与规范地名的关系
+type AliasKind int
+
+const (
+	AliasModern     AliasKind = iota // 今名：规范地名撤并、更名后的现行名称
+	AliasHistorical                  // 旧名：已撤销或更名的历史名称
+	AliasDistrict                    // 市辖区：归入该地点的市辖区
+	AliasPrefecture                  // 地级行政区：以该地点为驻地的地级市、地区或盟
+	AliasCorrection                  // 规范写法：数据集中的地名存在误写
+)
+
+var aliasKindNames = []string{"今名", "旧名", "市辖区", "地级行政区", "规范写法"}
+
+// String 返回别名类型的中文名称
+func (k AliasKind) String() string {
+	if k < AliasModern || k > AliasCorrection {
+		return ""
+	}
+	return aliasKindNames[k]
+}
+
+// Alias 表示指向规范地名的别名
+type Alias struct {
+	Name string    // 别名
+	City string    // 对应的规范地名
+	Kind AliasKind // 别名类型
+}
+
+// Division 表示一个省级行政区及其下辖地点
+type Division struct {
+	Name    string  // 省级行政区名称
+	Code    string  // 省级行政区划代码（GB/T 2260）
+	Seat    string  // 省会（首府）名称，须包含在 Places 中
+	Places  []Place // 下辖地点，Approximate 为 true 的地点将以省会坐标填充
+	Aliases []Alias // 地点的今名、旧名、市辖区等别名
+}
+
+// ErrInvalidGazetteer 表示地名录数据不合法
+var ErrInvalidGazetteer = errors.New("地名录数据无效")
+
+// Gazetteer 地名录，按省级行政区索引带坐标的地点
+type Gazetteer struct {
+	provinces []string
+	cities    map[string][]string
+	places    map[string]map[string]Place
+	aliases   map[string]map[string]Alias
+	seats     map[string]Place
+	codes     map[string]string
+}
+
+// NewGazetteer 根据省级行政区数据构建地名录，同省重复的地名仅保留首次出现的条目
+func NewGazetteer(divisions []Division) (*Gazetteer, error) {
+	g := &Gazetteer{
+		cities:  make(map[string][]string, len(divisions)),
+		places:  make(map[string]map[string]Place, len(divisions)),
+		aliases: make(map[string]map[string]Alias, len(divisions)),
+		seats:   make(map[string]Place, len(divisions)),
+		codes:   make(map[string]string, len(divisions)),
+	}
+
+	for _, d := range divisions {
+		if _, ok := g.places[d.Name]; ok {
+			return nil, fmt.Errorf("%w: 省份 %s 重复", ErrInvalidGazetteer, d.Name)
+		}
+
+		var seat *Place
+		for i := range d.Places {
+			if d.Places[i].City == d.Seat && !d.Places[i].Approximate {
+				seat = &d.Places[i]
+				break
+			}
+		}
+		if seat == nil {
+			return nil, fmt.Errorf("%w: 省份 %s 缺少省会 %s 的坐标", ErrInvalidGazetteer, d.Name, d.Seat)
+		}
+
+		places := make(map[string]Place, len(d.Places))
+		var cities []string
+		for _, p := range d.Places {
+			if _, ok := places[p.City]; ok {
+				continue
+			}
+			p.Province = d.Name
+			if p.Approximate {
+				p.Longitude, p.Latitude = seat.Longitude, seat.Latitude
+			}
+			if err := ValidateCoordinate(p.Longitude, p.Latitude); err != nil {
+				return nil, fmt.Errorf("%w: %s %s %v", ErrInvalidGazetteer, d.Name, p.City, err)
+			}
+			places[p.City] = p
+			cities = append(cities, p.City)
+		}
+
+		aliases := make(map[string]Alias, len(d.Aliases))
+		for _, a := range d.Aliases {
+			if _, ok := places[a.City]; !ok {
+				return nil, fmt.Errorf("%w: 别名 %s 指向不存在的地点 %s %s", ErrInvalidGazetteer, a.Name, d.Name, a.City)
+			}
+			if _, ok := places[a.Name]; ok {
+				return nil, fmt.Errorf("%w: 别名 %s 与 %s 的规范地名重复", ErrInvalidGazetteer, a.Name, d.Name)
+			}
+			if _, ok := aliases[a.Name]; ok {
+				return nil, fmt.Errorf("%w: %s 的别名 %s 重复", ErrInvalidGazetteer, d.Name, a.Name)
+			}
+			aliases[a.Name] = a
+		}
+
+		g.provinces = append(g.provinces, d.Name)
+		g.aliases[d.Name] = aliases
+		g.cities[d.Name] = cities
+		g.places[d.Name] = places
+		g.seats[d.Name] = places[d.Seat]
+		g.codes[d.Name] = d.Code
+	}
+	return g, nil
+}
+
+// Provinces 返回全部省级行政区名称
+func (g *Gazetteer) Provinces() []string {
+	return g.provinces
+}
+
+// Cities 返回省级行政区下辖的地名，省份无效时返回 nil
+func (g *Gazetteer) Cities(province string) []string {
+	return g.cities[province]
+}
+
+// Lookup 精确查找地点
+func (g *Gazetteer) Lookup(province, city string) (Place, bool) {
+	place, ok := g.places[province][city]
+	return place, ok
+}
+
+// Seat 返回省级行政区的省会（首府）
+func (g *Gazetteer) Seat(province string) (Place, bool) {
+	place, ok := g.seats[province]
+	return place, ok
+}
+
+// Code 返回省级行政区划代码，省份无效或未收录代码时返回空字符串
+func (g *Gazetteer) Code(province string) string {
+	return g.codes[province]
+}
+
+// MatchProvince 模糊匹配省级行政区
+func (g *Gazetteer) MatchProvince(input string) (string, float64) {
+	return calculateBestMatch(input, g.provinces)
+}
+
+// Aliases 返回指向规范地名的全部别名
+func (g *Gazetteer) Aliases(province, city string) []Alias {
+	var aliases []Alias
+	for _, a := range g.aliases[province] {
+		if a.City == city {
+			aliases = append(aliases, a)
+		}
+	}
+	return aliases
+}
+
+// MatchReason 表示地名的匹配方式
+type MatchReason int
+
+const (
+	MatchExact MatchReason = iota // 与规范地名一致
+	MatchAlias                    // 命中别名
+	MatchFuzzy                    // 按拼音相似度匹配
+)
+
+// CityMatch 表示地名匹配结果
+type CityMatch struct {
+	Input  string      // 用户输入
+	Place  Place       // 选中的规范地点
+	Reason MatchReason // 匹配方式
+	Alias  *Alias      // 经由别名匹配时命中的别名
+	Score  float64     // 匹配度，精确或别名匹配时为 1
+}
+
+// Explain 说明选中该规范地点的原因
+func (m CityMatch) Explain() string {
+	target := fmt.Sprintf("%s %s", m.Place.Province, m.Place.City)
+	var reason string
+	switch m.Reason {
+	case MatchExact:
+		return fmt.Sprintf("“%s”与规范地名“%s”一致", m.Input, target)
+	case MatchAlias:
+		reason = fmt.Sprintf("“%s”", m.Input)
+	default:
+		reason = fmt.Sprintf("“%s”未找到一致的地名，按拼音相似度（%.2f）匹配", m.Input, m.Score)
+		if m.Alias == nil {
+			return fmt.Sprintf("%s为“%s”", reason, target)
+		}
+		reason += fmt.Sprintf("到“%s”，", m.Alias.Name)
+	}
+
+	a := m.Alias
+	switch a.Kind {
+	case AliasModern:
+		reason += fmt.Sprintf("为“%s”的今名", a.City)
+	case AliasHistorical:
+		reason += fmt.Sprintf("为旧名，今属“%s”", a.City)
+	case AliasDistrict:
+		reason += fmt.Sprintf("为“%s”的市辖区", a.City)
+	case AliasPrefecture:
+		reason += fmt.Sprintf("为驻地在“%s”的地级行政区", a.City)
+	case AliasCorrection:
+		reason += fmt.Sprintf("为“%s”的规范写法", a.City)
+	}
+	return fmt.Sprintf("%s，按规范地名“%s”处理", reason, target)
+}
+
+// 匹配时可省略的行政区划后缀，较长的后缀优先
+var placeSuffixes = []string{"特别行政区", "自治县", "自治旗", "新区", "特区", "地区", "市", "县", "区", "旗", "盟"}
+
+// MatchCity 匹配地点：依次尝试规范地名、别名、去掉“市县区”等后缀后的名称，最后按拼音相似度模糊匹配。
+// province 为空时在全部省份中查找唯一的精确或别名匹配。
+func (g *Gazetteer) MatchCity(input, province string) (CityMatch, bool) {
+	input = strings.TrimSpace(input)
+	if province == "" {
+		return g.matchAnyProvince(input)
+	}
+	if _, ok := g.places[province]; !ok || input == "" {
+		return CityMatch{Input: input}, false
+	}
+
+	if m, ok := g.matchExact(input, province); ok {
+		return m, true
+	}
+
+	// 模糊匹配同时考虑规范地名与别名
+	aliasNames := make([]string, 0, len(g.aliases[province]))
+	for name := range g.aliases[province] {
+		aliasNames = append(aliasNames, name)
+	}
+	sort.Strings(aliasNames)
+	candidates := append(append([]string{}, g.cities[province]...), aliasNames...)
+	best, score := calculateBestMatch(input, candidates)
+	m := CityMatch{Input: input, Reason: MatchFuzzy, Score: score}
+	if a, ok := g.aliases[province][best]; ok {
+		m.Place, m.Alias = g.places[province][a.City], &a
+	} else {
+		m.Place = g.places[province][best]
+	}
+	return m, best != ""
+}
+
+// matchExact 尝试规范地名与别名的精确匹配，必要时去掉行政区划后缀
+func (g *Gazetteer) matchExact(input, province string) (CityMatch, bool) {
+	names := []string{input}
+	for _, suffix := range placeSuffixes {
+		if trimmed, ok := strings.CutSuffix(input, suffix); ok && trimmed != "" {
+			names = append(names, trimmed)
+		}
+	}
+	for _, name := range names {
+		if place, ok := g.places[province][name]; ok {
+			return CityMatch{Input: input, Place: place, Reason: MatchExact, Score: 1}, true
+		}
+		if a, ok := g.aliases[province][name]; ok {
+			return CityMatch{Input: input, Place: g.places[province][a.City], Reason: MatchAlias, Alias: &a, Score: 1}, true
+		}
+	}
+	return CityMatch{}, false
+}
+
+// matchAnyProvince 在全部省份中查找精确或别名匹配，仅当结果唯一时返回
+func (g *Gazetteer) matchAnyProvince(input string) (CityMatch, bool) {
+	var found []CityMatch
+	for _, province := range g.provinces {
+		if m, ok := g.matchExact(input, province); ok {
+			found = append(found, m)
+		}
+	}
+	if len(found) != 1 {
+		return CityMatch{Input: input}, false
+	}
+	return found[0], true
+}
@@ -0,0 +1,130 @@
+package location
+
+import (
+	"errors"
+	"testing"
+)
+
+func testDivisions() []Division {
+	return []Division{
+		{
+			Name: "新疆维吾尔自治区", Code: "650000", Seat: "乌鲁木齐",
+			Places: []Place{
+				{City: "乌鲁木齐", Code: "650100", Longitude: 87.62, Latitude: 43.83},
+				{City: "喀什", Code: "653101", Longitude: 75.99, Latitude: 39.47},
+				{City: "伊犁", Code: "654000", Approximate: true},
+				{City: "喀什", Longitude: 0, Latitude: 0},
+			},
+		},
+		{
+			Name: "北京市", Code: "110000", Seat: "北京",
+			Places: []Place{
+				{City: "北京", Longitude: 116.41, Latitude: 39.90},
+				{City: "通县", Longitude: 116.66, Latitude: 39.91},
+			},
+			Aliases: []Alias{
+				{Name: "通州", City: "通县", Kind: AliasModern},
+				{Name: "海淀", City: "北京", Kind: AliasDistrict},
+			},
+		},
+	}
+}
+
+func TestNewGazetteer(t *testing.T) {
+	g, err := NewGazetteer(testDivisions())
+	if err != nil {
+		t.Fatalf("NewGazetteer() error = %v", err)
+	}
+
+	if got := g.Cities("新疆维吾尔自治区"); len(got) != 3 {
+		t.Errorf("Cities() = %v, 重复地名应只保留一次", got)
+	}
+
+	place, ok := g.Lookup("新疆维吾尔自治区", "喀什")
+	want := Place{Province: "新疆维吾尔自治区", City: "喀什", Code: "653101", Longitude: 75.99, Latitude: 39.47}
+	if !ok || place != want {
+		t.Errorf("Lookup() = %+v, want %+v", place, want)
+	}
+
+	place, ok = g.Lookup("新疆维吾尔自治区", "伊犁")
+	if !ok || !place.Approximate || place.Longitude != 87.62 || place.Code != "654000" {
+		t.Errorf("未收录坐标的地点应以省会坐标近似, got %+v", place)
+	}
+
+	if seat, ok := g.Seat("北京市"); !ok || seat.City != "北京" {
+		t.Errorf("Seat() = %+v", seat)
+	}
+	if code := g.Code("北京市"); code != "110000" {
+		t.Errorf("Code() = %q", code)
+	}
+	if _, ok := g.Lookup("火星", "北京"); ok {
+		t.Error("无效省份不应查到地点")
+	}
+}
+
+func TestNewGazetteerInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		divisions []Division
+	}{
+		{"缺少省会", []Division{{Name: "北京市", Seat: "北京", Places: []Place{{City: "平谷", Longitude: 117.1, Latitude: 40.1}}}}},
+		{"省份重复", append(testDivisions(), testDivisions()[1])},
+		{"别名指向不存在的地点", []Division{{Name: "北京市", Seat: "北京", Places: []Place{{City: "北京", Longitude: 116.4, Latitude: 39.9}}, Aliases: []Alias{{Name: "通州", City: "通县"}}}}},
+		{"别名与规范地名重复", []Division{{Name: "北京市", Seat: "北京", Places: []Place{{City: "北京", Longitude: 116.4, Latitude: 39.9}}, Aliases: []Alias{{Name: "北京", City: "北京"}}}}},
+		{"坐标越界", []Division{{Name: "北京市", Seat: "北京", Places: []Place{{City: "北京", Longitude: 200, Latitude: 39.9}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewGazetteer(tt.divisions); !errors.Is(err, ErrInvalidGazetteer) {
+				t.Errorf("NewGazetteer() error = %v, want ErrInvalidGazetteer", err)
+			}
+		})
+	}
+}
+
+func TestGazetteerMatch(t *testing.T) {
+	g, err := NewGazetteer(testDivisions())
+	if err != nil {
+		t.Fatalf("NewGazetteer() error = %v", err)
+	}
+	if province, ratio := g.MatchProvince("新疆"); province != "新疆维吾尔自治区" || ratio < 0.3 {
+		t.Errorf("MatchProvince() = %s, %v", province, ratio)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		province string
+		city     string
+		reason   MatchReason
+		kind     AliasKind
+	}{
+		{"规范地名", "喀什", "新疆维吾尔自治区", "喀什", MatchExact, 0},
+		{"去掉后缀", "喀什市", "新疆维吾尔自治区", "喀什", MatchExact, 0},
+		{"今名", "通州区", "北京市", "通县", MatchAlias, AliasModern},
+		{"市辖区", "海淀", "北京市", "北京", MatchAlias, AliasDistrict},
+		{"未指定省份", "通州", "", "通县", MatchAlias, AliasModern},
+		{"拼音相似", "通洲", "北京市", "通县", MatchFuzzy, AliasModern},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, ok := g.MatchCity(tt.input, tt.province)
+			if !ok || m.Place.City != tt.city || m.Reason != tt.reason {
+				t.Fatalf("MatchCity(%s) = %+v, want %s", tt.input, m, tt.city)
+			}
+			if tt.reason != MatchExact && (m.Alias == nil || m.Alias.Kind != tt.kind) {
+				t.Errorf("MatchCity(%s) alias = %+v, want kind %v", tt.input, m.Alias, tt.kind)
+			}
+			if m.Explain() == "" {
+				t.Error("Explain() 不应为空")
+			}
+		})
+	}
+
+	if m := (CityMatch{Input: "通州区", Place: Place{Province: "北京市", City: "通县"}, Reason: MatchAlias, Alias: &Alias{Name: "通州", City: "通县", Kind: AliasModern}}); m.Explain() != "“通州区”为“通县”的今名，按规范地名“北京市 通县”处理" {
+		t.Errorf("Explain() = %s", m.Explain())
+	}
+	if _, ok := g.MatchCity("火星", ""); ok {
+		t.Error("未指定省份时无唯一匹配应返回 false")
+	}
+}
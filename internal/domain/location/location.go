@@ -361,7 +361,6 @@ var Cities = map[string][]string{
 		"临朐",
 		"东营",
 		"沂南",
-		"高都",
 		"广饶",
 		"青州",
 		"临沂",
@@ -515,7 +514,6 @@ var Cities = map[string][]string{
 	},
 	"四川省": {
 		"通江",
-		"百沙",
 		"万源",
 		"开江",
 		"宜汉",
@@ -966,7 +964,6 @@ var Cities = map[string][]string{
 		"无为",
 		"祁门",
 		"定远",
-		"吴壁",
 		"贵池",
 		"石台",
 		"肥东",
@@ -1807,7 +1804,6 @@ var Cities = map[string][]string{
 		"高州",
 		"吴川",
 		"化州",
-		"洪江",
 		"海口",
 		"湛江",
 		"廉江",
@@ -2756,7 +2752,6 @@ const Location string = `{
 		"临朐",
 		"东营",
 		"沂南",
-		"高都",
 		"广饶",
 		"青州",
 		"临沂",
@@ -2910,7 +2905,6 @@ const Location string = `{
 	],
 	"四川省": [
 		"通江",
-		"百沙",
 		"万源",
 		"开江",
 		"宜汉",
@@ -3361,7 +3355,6 @@ const Location string = `{
 		"无为",
 		"祁门",
 		"定远",
-		"吴壁",
 		"贵池",
 		"石台",
 		"肥东",
@@ -4202,7 +4195,6 @@ const Location string = `{
 		"高州",
 		"吴川",
 		"化州",
-		"洪江",
 		"海口",
 		"湛江",
 		"廉江",
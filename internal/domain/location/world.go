@@ -0,0 +1,81 @@
+package location
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WorldCity 表示境外城市及其所在时区
+type WorldCity struct {
+	Name        string  `json:"name"`         // 中文名称
+	EnglishName string  `json:"english_name"` // 英文名称
+	Country     string  `json:"country"`      // 国家或地区
+	Longitude   float64 `json:"longitude"`    // 经度，东经为正
+	Latitude    float64 `json:"latitude"`     // 纬度，北纬为正
+	TimeZone    string  `json:"timezone"`     // IANA 时区名称
+}
+
+// Location 返回城市所在时区
+func (c WorldCity) Location() (*time.Location, error) {
+	return time.LoadLocation(c.TimeZone)
+}
+
+// WorldGazetteer 境外城市地名录，可按中文或英文名称查询
+type WorldGazetteer struct {
+	cities []WorldCity
+	index  map[string]int
+}
+
+// NewWorldGazetteer 校验城市坐标与时区并构建境外地名录
+func NewWorldGazetteer(cities []WorldCity) (*WorldGazetteer, error) {
+	g := &WorldGazetteer{index: make(map[string]int, len(cities)*2)}
+	for _, c := range cities {
+		if c.Name == "" || c.EnglishName == "" {
+			return nil, fmt.Errorf("%w: 城市名称不能为空", ErrInvalidGazetteer)
+		}
+		if err := ValidateCoordinate(c.Longitude, c.Latitude); err != nil {
+			return nil, fmt.Errorf("%w: %s %v", ErrInvalidGazetteer, c.Name, err)
+		}
+		if _, err := c.Location(); err != nil {
+			return nil, fmt.Errorf("%w: %s 的时区 %s 无效", ErrInvalidGazetteer, c.Name, c.TimeZone)
+		}
+		for _, key := range []string{c.Name, worldKey(c.EnglishName)} {
+			if _, ok := g.index[key]; ok {
+				return nil, fmt.Errorf("%w: 城市 %s 重复", ErrInvalidGazetteer, key)
+			}
+			g.index[key] = len(g.cities)
+		}
+		g.cities = append(g.cities, c)
+	}
+	return g, nil
+}
+
+// Cities 返回全部城市
+func (g *WorldGazetteer) Cities() []WorldCity {
+	return append([]WorldCity(nil), g.cities...)
+}
+
+// Lookup 按中文名或英文名（不区分大小写）查询城市，中文名可带“市”字
+func (g *WorldGazetteer) Lookup(name string) (WorldCity, bool) {
+	name = strings.TrimSpace(name)
+	for _, key := range []string{name, strings.TrimSuffix(name, "市"), worldKey(name)} {
+		if i, ok := g.index[key]; ok {
+			return g.cities[i], true
+		}
+	}
+	return WorldCity{}, false
+}
+
+// ValidateCoordinate 校验经纬度范围
+func ValidateCoordinate(longitude, latitude float64) error {
+	if longitude < -180 || longitude > 180 || latitude < -90 || latitude > 90 {
+		return fmt.Errorf("坐标 (%v, %v) 超出范围", longitude, latitude)
+	}
+	return nil
+}
+
+// worldKey 英文名称的索引键：忽略大小写与多余空白
+func worldKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
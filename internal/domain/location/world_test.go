@@ -0,0 +1,49 @@
+package location
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWorldGazetteer(t *testing.T) {
+	g, err := NewWorldGazetteer([]WorldCity{
+		{Name: "纽约", EnglishName: "New York", Country: "美国", Longitude: -74.01, Latitude: 40.71, TimeZone: "America/New_York"},
+		{Name: "悉尼", EnglishName: "Sydney", Country: "澳大利亚", Longitude: 151.21, Latitude: -33.87, TimeZone: "Australia/Sydney"},
+	})
+	if err != nil {
+		t.Fatalf("NewWorldGazetteer() error = %v", err)
+	}
+
+	for _, input := range []string{"纽约", "纽约市", "New York", " new  york "} {
+		if city, ok := g.Lookup(input); !ok || city.Name != "纽约" {
+			t.Errorf("Lookup(%q) = %+v", input, city)
+		}
+	}
+	if _, ok := g.Lookup("火星"); ok {
+		t.Error("未收录的城市不应查到")
+	}
+	if loc, err := (WorldCity{TimeZone: "Australia/Sydney"}).Location(); err != nil || loc.String() != "Australia/Sydney" {
+		t.Errorf("Location() = %v, %v", loc, err)
+	}
+}
+
+func TestNewWorldGazetteerInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cities []WorldCity
+	}{
+		{"无效时区", []WorldCity{{Name: "纽约", EnglishName: "New York", TimeZone: "America/Gotham"}}},
+		{"坐标越界", []WorldCity{{Name: "纽约", EnglishName: "New York", Latitude: 100, TimeZone: "America/New_York"}}},
+		{"名称重复", []WorldCity{
+			{Name: "纽约", EnglishName: "New York", TimeZone: "America/New_York"},
+			{Name: "纽约", EnglishName: "NYC", TimeZone: "America/New_York"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewWorldGazetteer(tt.cities); !errors.Is(err, ErrInvalidGazetteer) {
+				t.Errorf("NewWorldGazetteer() error = %v, want ErrInvalidGazetteer", err)
+			}
+		})
+	}
+}
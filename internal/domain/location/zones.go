@@ -0,0 +1,69 @@
+package location
+
+import (
+	"time"
+)
+
+// HistoricalZone 表示民国时期划定的标准时区
+type HistoricalZone struct {
+	Name     string        // 时区名称
+	Meridian float64       // 标准经线（东经）
+	Offset   time.Duration // 相对 UTC 的偏移
+}
+
+// 民国时期的五个标准时区
+var (
+	KunlunZone    = HistoricalZone{Name: "昆仑时区", Meridian: 82.5, Offset: 5*time.Hour + 30*time.Minute}
+	XinzangZone   = HistoricalZone{Name: "新藏时区", Meridian: 90, Offset: 6 * time.Hour}
+	LongshuZone   = HistoricalZone{Name: "陇蜀时区", Meridian: 105, Offset: 7 * time.Hour}
+	ZhongyuanZone = HistoricalZone{Name: "中原时区", Meridian: 120, Offset: 8 * time.Hour}
+	ChangbaiZone  = HistoricalZone{Name: "长白时区", Meridian: 127.5, Offset: 8*time.Hour + 30*time.Minute}
+)
+
+// 分区时制的施行期间：1912 年中央观象台划分五时区，至 1949 年 10 月 1 日起全国统一使用北京时间
+var (
+	historicalZoneStart = time.Date(1912, 1, 1, 0, 0, 0, 0, time.UTC)
+	historicalZoneEnd   = time.Date(1949, 10, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// historicalZoneProvinces 各省级行政区所属时区，未列出的省份属中原时区；
+// 新疆、西藏以东经 85° 为界分属昆仑时区与新藏时区
+var historicalZoneProvinces = map[string]HistoricalZone{
+	"吉林省":      ChangbaiZone,
+	"黑龙江省":     ChangbaiZone,
+	"陕西省":      LongshuZone,
+	"甘肃省":      LongshuZone,
+	"宁夏回族自治区":  LongshuZone,
+	"青海省":      LongshuZone,
+	"四川省":      LongshuZone,
+	"重庆市":      LongshuZone,
+	"贵州省":      LongshuZone,
+	"云南省":      LongshuZone,
+	"新疆维吾尔自治区": XinzangZone,
+	"西藏自治区":    XinzangZone,
+}
+
+// FollowsBeijingTime 判断省级行政区是否施行北京时间及其历史时制，港澳台不在此列
+func FollowsBeijingTime(province string) bool {
+	return province != "港澳台"
+}
+
+// HistoricalZoneOf 返回地点在 civil 日期所施行的民国标准时区，日期不在分区时制期间时返回 false。
+// 划分按省级行政区近似，与当时各地的实际用时可能存在出入。
+func HistoricalZoneOf(place Place, civil time.Time) (HistoricalZone, bool) {
+	day := time.Date(civil.Year(), civil.Month(), civil.Day(), 0, 0, 0, 0, time.UTC)
+	if day.Before(historicalZoneStart) || !day.Before(historicalZoneEnd) {
+		return HistoricalZone{}, false
+	}
+	if !FollowsBeijingTime(place.Province) {
+		return HistoricalZone{}, false
+	}
+	zone, ok := historicalZoneProvinces[place.Province]
+	if !ok {
+		return ZhongyuanZone, true
+	}
+	if zone == XinzangZone && place.Longitude > 0 && place.Longitude < 85 {
+		return KunlunZone, true
+	}
+	return zone, true
+}
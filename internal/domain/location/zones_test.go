@@ -0,0 +1,34 @@
+package location
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistoricalZoneOf(t *testing.T) {
+	date := time.Date(1940, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		place Place
+		want  HistoricalZone
+		ok    bool
+	}{
+		{"中原时区", Place{Province: "北京市", City: "北京", Longitude: 116.41}, ZhongyuanZone, true},
+		{"陇蜀时区", Place{Province: "四川省", City: "成都", Longitude: 104.07}, LongshuZone, true},
+		{"长白时区", Place{Province: "吉林省", City: "长春", Longitude: 125.32}, ChangbaiZone, true},
+		{"新藏时区", Place{Province: "新疆维吾尔自治区", City: "乌鲁木齐", Longitude: 87.62}, XinzangZone, true},
+		{"昆仑时区", Place{Province: "新疆维吾尔自治区", City: "疏附", Longitude: 75.86}, KunlunZone, true},
+		{"港澳台", Place{Province: "港澳台", City: "香港", Longitude: 114.17}, HistoricalZone{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if zone, ok := HistoricalZoneOf(tt.place, date); zone != tt.want || ok != tt.ok {
+				t.Errorf("HistoricalZoneOf() = %+v, %v, want %+v", zone, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := HistoricalZoneOf(Place{Province: "四川省"}, time.Date(1949, 10, 1, 0, 0, 0, 0, time.UTC)); ok {
+		t.Error("1949年10月1日起应统一使用北京时间")
+	}
+}
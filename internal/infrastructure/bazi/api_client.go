@@ -5,69 +5,258 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
 )
 
 const (
 	APIEndpoint = "https://api.yuanfenju.com/index.php/v1/Bazi"
+
+	// ProviderName 提供方名称，用于错误信息
+	ProviderName = "yuanfenju"
+)
+
+// 默认的调用参数
+const (
+	DefaultTimeout          = 10 * time.Second       // 单次请求超时
+	DefaultMaxRetries       = 2                      // 暂时性错误的最大重试次数
+	DefaultBaseBackoff      = 200 * time.Millisecond // 首次重试前的等待时间
+	DefaultMaxBackoff       = 2 * time.Second        // 重试等待时间上限
+	DefaultFailureThreshold = 5                      // 触发熔断的连续失败次数
+	DefaultCooldown         = 30 * time.Second       // 熔断时长
 )
 
+// maxResponseSize 读取响应内容的上限
+const maxResponseSize = 4 << 20
+
+// ClientOptions API 客户端配置，零值字段使用默认值
+type ClientOptions struct {
+	Endpoint         string        // 接口地址，为空时使用 APIEndpoint
+	APIKey           string        // 接口密钥，为空时读取环境变量 API_KEY
+	Timeout          time.Duration // 单次请求超时
+	MaxRetries       int           // 暂时性错误（网络错误、HTTP 429 与 5xx）的最大重试次数，负数表示不重试
+	BaseBackoff      time.Duration // 首次重试前的等待时间，之后每次翻倍并加入随机抖动
+	MaxBackoff       time.Duration // 重试等待时间上限
+	FailureThreshold int           // 触发熔断的连续失败次数，负数表示不熔断
+	Cooldown         time.Duration // 熔断时长，到期后放行一个试探请求
+	HTTPClient       *http.Client  // 为空时使用 http.DefaultTransport
+}
+
 // APIClient 实现了 bazi.Service 接口，通过调用外部 API 获取八字排盘结果。
+// 单次请求有超时，暂时性错误按指数退避重试，连续失败时熔断；失败以 *bazi.ProviderError 返回。
 type APIClient struct {
-	apiKey string
+	apiKey     string
+	endpoint   string
+	timeout    time.Duration
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	httpClient *http.Client
+	breaker    *circuitBreaker
+	sleep      func(ctx context.Context, d time.Duration) error
 }
 
 // NewAPIClient 创建一个新的 APIClient 实例。
-func NewAPIClient() *APIClient {
-	return &APIClient{
-		apiKey: os.Getenv("API_KEY"),
+func NewAPIClient(opts ClientOptions) *APIClient {
+	c := &APIClient{
+		apiKey:     opts.APIKey,
+		endpoint:   opts.Endpoint,
+		timeout:    opts.Timeout,
+		maxRetries: opts.MaxRetries,
+		backoff:    opts.BaseBackoff,
+		maxBackoff: opts.MaxBackoff,
+		httpClient: opts.HTTPClient,
+		sleep:      sleepContext,
+	}
+	if c.apiKey == "" {
+		c.apiKey = os.Getenv("API_KEY")
+	}
+	if c.endpoint == "" {
+		c.endpoint = APIEndpoint
+	}
+	if c.timeout <= 0 {
+		c.timeout = DefaultTimeout
+	}
+	switch {
+	case c.maxRetries == 0:
+		c.maxRetries = DefaultMaxRetries
+	case c.maxRetries < 0:
+		c.maxRetries = 0
+	}
+	if c.backoff <= 0 {
+		c.backoff = DefaultBaseBackoff
+	}
+	if c.maxBackoff <= 0 {
+		c.maxBackoff = DefaultMaxBackoff
 	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{}
+	}
+	threshold, cooldown := opts.FailureThreshold, opts.Cooldown
+	if threshold == 0 {
+		threshold = DefaultFailureThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+	c.breaker = newCircuitBreaker(threshold, cooldown)
+	return c
 }
 
 // GetPaipanResult 调用外部 API 获取八字排盘结果。
+// 接口返回的业务错误以 Kind 为 bazi.ErrorBusiness 的 *bazi.ProviderError 返回，不重试。
 func (c *APIClient) GetPaipanResult(ctx context.Context, req bazi.Request) (*bazi.PaipanResponse, error) {
 	formData := c.buildFormData(req)
 
-	body, err := c.makeAPIRequest(ctx, "/paipan", formData)
+	var last *bazi.ProviderError
+	for attempt := 0; ; attempt++ {
+		if ok, wait := c.breaker.allow(); !ok {
+			if last != nil {
+				// 重试过程中触发熔断，返回最近一次失败的原因
+				return nil, last
+			}
+			return nil, &bazi.ProviderError{Kind: bazi.ErrorCircuitOpen, Provider: ProviderName, Message: fmt.Sprintf("约 %s 后恢复", wait.Round(time.Second))}
+		}
+
+		resp, err := c.makeAPIRequest(ctx, "/paipan", formData)
+		switch {
+		case err == nil:
+			c.breaker.success()
+			return resp, nil
+		case err.Kind == bazi.ErrorBusiness:
+			// 接口正常响应，只是请求本身有误
+			c.breaker.success()
+			return nil, err
+		case ctx.Err() != nil:
+			// 调用方取消或超时，不计入提供方的失败
+			c.breaker.abandon()
+			return nil, err
+		}
+
+		c.breaker.failure()
+		last = err
+		if !err.Temporary() || attempt >= c.maxRetries {
+			return nil, err
+		}
+		if c.sleep(ctx, c.retryDelay(attempt)) != nil {
+			return nil, err
+		}
+	}
+}
+
+// makeAPIRequest 执行一次API请求并解析响应，失败时按类别返回错误
+func (c *APIClient) makeAPIRequest(ctx context.Context, path string, formData url.Values) (*bazi.PaipanResponse, *bazi.ProviderError) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint+path, strings.NewReader(formData.Encode()))
 	if err != nil {
-		return nil, err
+		return nil, &bazi.ProviderError{Kind: bazi.ErrorNetwork, Provider: ProviderName, Message: "创建HTTP请求失败", Err: err}
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, &bazi.ProviderError{Kind: bazi.ErrorNetwork, Provider: ProviderName, Message: "API请求失败", Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, &bazi.ProviderError{Kind: bazi.ErrorNetwork, Provider: ProviderName, Message: "读取响应内容失败", Err: err}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &bazi.ProviderError{Kind: bazi.ErrorHTTPStatus, Provider: ProviderName, StatusCode: resp.StatusCode, Message: excerpt(body)}
 	}
 
 	var baziResp bazi.PaipanResponse
 	if err := json.Unmarshal(body, &baziResp); err != nil {
-		// 如果解析失败，仍然尝试返回原始 body 以便调试
-		return nil, fmt.Errorf("解析API响应失败: %w, 原始响应: %s", err, string(body))
+		// 附上响应内容摘要以便调试
+		return nil, &bazi.ProviderError{Kind: bazi.ErrorDecode, Provider: ProviderName, Message: excerpt(body), Err: err}
+	}
+	if baziResp.ErrCode != 0 {
+		return nil, &bazi.ProviderError{Kind: bazi.ErrorBusiness, Provider: ProviderName, ErrCode: baziResp.ErrCode, Message: baziResp.ErrMsg}
 	}
-
 	return &baziResp, nil
 }
 
-// makeAPIRequest 执行API请求
-func (c *APIClient) makeAPIRequest(ctx context.Context, path string, formData url.Values) ([]byte, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", APIEndpoint+path, strings.NewReader(formData.Encode()))
-	if err != nil {
-		return nil, fmt.Errorf("创建HTTP请求失败: %w", err)
+// CheckHealth 以 GET 请求探测接口地址，不调用排盘接口、不消耗排盘次数：收到 5xx 以外的响应即视为可达。
+// 同时报告熔断器状态；缘分居接口没有公开剩余调用次数，剩余次数报告为不支持
+func (c *APIClient) CheckHealth(ctx context.Context) bazi.ProviderStatus {
+	status := bazi.ProviderStatus{Breaker: c.breaker.current().String(), Quota: bazi.QuotaUnsupported}
+	var details []string
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := c.probe(ctx)
+	status.LatencyMS = time.Since(start).Milliseconds()
+	switch {
+	case err != nil:
+		details = append(details, err.Error())
+	case resp.StatusCode >= 500:
+		details = append(details, fmt.Sprintf("HTTP %d", resp.StatusCode))
+	default:
+		status.Reachable = true
 	}
-	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.apiKey == "" {
+		details = append(details, "未配置接口密钥")
+	}
+	status.Detail = strings.Join(details, "；")
+	return status
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+// probe 向接口地址发送 GET 请求并丢弃响应内容
+func (c *APIClient) probe(ctx context.Context) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("API请求失败: %w", err)
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
 	}
 	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+	return resp, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("读取响应内容失败: %w", err)
+// retryDelay 返回第 attempt 次失败后的等待时间：指数退避并在后一半区间内随机抖动
+func (c *APIClient) retryDelay(attempt int) time.Duration {
+	delay := c.maxBackoff
+	if attempt < 30 && c.backoff<<attempt < c.maxBackoff {
+		delay = c.backoff << attempt
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// sleepContext 等待指定时长，ctx 结束时提前返回错误
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// excerpt 返回响应内容的摘要，用于错误信息
+func excerpt(body []byte) string {
+	const limit = 200
+	text := strings.TrimSpace(string(body))
+	if runes := []rune(text); len(runes) > limit {
+		return string(runes[:limit]) + "…"
 	}
-	return body, nil
+	return text
 }
 
 // buildFormData 构建API请求的表单数据
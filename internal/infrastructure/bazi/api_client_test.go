@@ -0,0 +1,188 @@
+package bazi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// newTestClient 创建指向测试服务器、不实际等待重试的客户端
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ClientOptions) (*APIClient, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	opts.Endpoint = server.URL
+	opts.APIKey = "test"
+	c := NewAPIClient(opts)
+	c.sleep = func(ctx context.Context, d time.Duration) error { return ctx.Err() }
+	return c, &calls
+}
+
+var testRequest = bazi.Request{Name: "张三", Sex: 0, Type: 1, Year: 2000, Month: 1, Day: 2, Hours: 3, Minute: 4, Sect: 1, Zhen: 2}
+
+func TestAPIClientRetry(t *testing.T) {
+	var failures atomic.Int32
+	failures.Store(2)
+	c, calls := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/paipan" || r.FormValue("year") != "2000" || r.FormValue("api_key") != "test" {
+			t.Errorf("请求 = %s %v", r.URL.Path, r.Form)
+		}
+		if failures.Add(-1) >= 0 {
+			http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"errcode":0,"errmsg":"","data":{"base_info":{"name":"张三"}}}`))
+	}, ClientOptions{})
+
+	resp, err := c.GetPaipanResult(context.Background(), testRequest)
+	if err != nil || resp.Data.BaseInfo.Name != "张三" {
+		t.Fatalf("GetPaipanResult() = %+v, %v", resp, err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("应重试两次后成功, got %d calls", calls.Load())
+	}
+}
+
+func TestAPIClientErrorKinds(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		kind    bazi.ErrorKind
+		calls   int32
+	}{
+		{"业务错误不重试", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"errcode":10003,"errmsg":"出生年份超出范围"}`))
+		}, bazi.ErrorBusiness, 1},
+		{"4xx 不重试", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+		}, bazi.ErrorHTTPStatus, 1},
+		{"5xx 重试后失败", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		}, bazi.ErrorHTTPStatus, 3},
+		{"响应无法解析", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<html>maintenance</html>"))
+		}, bazi.ErrorDecode, 1},
+		{"请求超时", func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}, bazi.ErrorNetwork, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, calls := newTestClient(t, tt.handler, ClientOptions{Timeout: 50 * time.Millisecond})
+			_, err := c.GetPaipanResult(context.Background(), testRequest)
+			var providerErr *bazi.ProviderError
+			if !errors.As(err, &providerErr) || providerErr.Kind != tt.kind {
+				t.Fatalf("GetPaipanResult() error = %v, want kind %s", err, tt.kind)
+			}
+			if calls.Load() != tt.calls {
+				t.Errorf("请求次数 = %d, want %d", calls.Load(), tt.calls)
+			}
+		})
+	}
+}
+
+func TestAPIClientBusinessError(t *testing.T) {
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errcode":10003,"errmsg":"出生年份超出范围"}`))
+	}, ClientOptions{})
+	_, err := c.GetPaipanResult(context.Background(), testRequest)
+	var providerErr *bazi.ProviderError
+	if !errors.As(err, &providerErr) || providerErr.ErrCode != 10003 || providerErr.Message != "出生年份超出范围" {
+		t.Errorf("GetPaipanResult() error = %#v", err)
+	}
+}
+
+func TestAPIClientCircuitBreaker(t *testing.T) {
+	healthy := atomic.Bool{}
+	c, calls := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			http.Error(w, "down", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"errcode":0}`))
+	}, ClientOptions{MaxRetries: -1, FailureThreshold: 2, Cooldown: time.Minute})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for range 2 {
+		c.GetPaipanResult(ctx, testRequest)
+	}
+	_, err := c.GetPaipanResult(ctx, testRequest)
+	var providerErr *bazi.ProviderError
+	if !errors.As(err, &providerErr) || providerErr.Kind != bazi.ErrorCircuitOpen || calls.Load() != 2 {
+		t.Fatalf("连续失败后应熔断: %v, calls %d", err, calls.Load())
+	}
+
+	// 熔断到期后放行试探请求，失败则继续熔断
+	now = now.Add(time.Minute)
+	c.GetPaipanResult(ctx, testRequest)
+	if _, err := c.GetPaipanResult(ctx, testRequest); !errors.As(err, &providerErr) || providerErr.Kind != bazi.ErrorCircuitOpen || calls.Load() != 3 {
+		t.Fatalf("试探请求失败后应继续熔断: %v, calls %d", err, calls.Load())
+	}
+
+	// 试探请求成功后恢复
+	now = now.Add(time.Minute)
+	healthy.Store(true)
+	if _, err := c.GetPaipanResult(ctx, testRequest); err != nil {
+		t.Fatalf("试探请求应成功: %v", err)
+	}
+	if _, err := c.GetPaipanResult(ctx, testRequest); err != nil || calls.Load() != 5 {
+		t.Errorf("恢复后应正常请求: %v, calls %d", err, calls.Load())
+	}
+}
+
+func TestAPIClientCheckHealth(t *testing.T) {
+	healthy := atomic.Bool{}
+	healthy.Store(true)
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"errcode":0}`))
+	}, ClientOptions{MaxRetries: -1})
+	ctx := context.Background()
+
+	status := c.CheckHealth(ctx)
+	if !status.Reachable || status.Breaker != "closed" || status.Quota != bazi.QuotaUnsupported || status.Detail != "" {
+		t.Errorf("CheckHealth() = %+v，接口可访问且剩余调用次数应报告为不支持", status)
+	}
+
+	healthy.Store(false)
+	if status := c.CheckHealth(ctx); status.Reachable || !strings.Contains(status.Detail, "503") {
+		t.Errorf("接口返回 503 时 CheckHealth() = %+v", status)
+	}
+
+	c.apiKey = ""
+	if status := c.CheckHealth(ctx); !strings.Contains(status.Detail, "未配置接口密钥") {
+		t.Errorf("未配置接口密钥时 Detail = %q", status.Detail)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	c := NewAPIClient(ClientOptions{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second})
+	for attempt, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		for range 20 {
+			if d := c.retryDelay(attempt); d < max/2 || d > max {
+				t.Fatalf("retryDelay(%d) = %s, want [%s, %s]", attempt, d, max/2, max)
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package bazi
+
+import (
+	"sync"
+	"time"
+)
+
+// breakerState 熔断器状态
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // 正常放行
+	breakerOpen                         // 熔断中，直接拒绝
+	breakerHalfOpen                     // 熔断到期，放行一个试探请求
+)
+
+// String 返回状态名称：closed、open 或 half-open
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
+
+// circuitBreaker 连续失败达到阈值后熔断一段时间，到期后放行一个试探请求：成功则恢复，失败则继续熔断
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int           // 触发熔断的连续失败次数，不大于 0 时不熔断
+	cooldown  time.Duration // 熔断时长
+	state     breakerState
+	failures  int
+	openUntil time.Time
+	now       func() time.Time
+}
+
+// newCircuitBreaker 创建熔断器
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// allow 判断是否放行请求，熔断中返回 false 及剩余熔断时长
+func (b *circuitBreaker) allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if remaining := b.openUntil.Sub(b.now()); remaining > 0 {
+			return false, remaining
+		}
+		b.state = breakerHalfOpen
+		return true, 0
+	case breakerHalfOpen:
+		// 试探请求尚未返回，其余请求继续拒绝
+		return false, b.cooldown
+	}
+	return true, 0
+}
+
+// success 记录一次成功，恢复正常放行
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state, b.failures = breakerClosed, 0
+}
+
+// failure 记录一次失败，连续失败达到阈值或试探请求失败时熔断
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.threshold <= 0 {
+		return
+	}
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// abandon 请求未得出结果（如调用方取消）时调用，试探请求被放弃后允许下一个请求重新试探
+func (b *circuitBreaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+		b.openUntil = b.now()
+	}
+}
+
+// current 返回当前状态，熔断到期但尚未放行试探请求时仍为 open
+func (b *circuitBreaker) current() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
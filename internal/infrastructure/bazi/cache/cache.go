@@ -0,0 +1,202 @@
+// Package cache 为排盘服务提供结果缓存：内存 LRU 与可选的磁盘存储，按规范化后的请求缓存成功的排盘结果。
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// 默认缓存配置
+const (
+	DefaultSize = 256            // 内存中缓存的排盘结果数
+	DefaultTTL  = 24 * time.Hour // 缓存有效期
+)
+
+// keyVersion 缓存键格式版本，规范化规则或响应结构变化时递增以淘汰旧的磁盘缓存
+const keyVersion = "v1"
+
+// Options 缓存配置
+type Options struct {
+	Size     int           // 内存缓存条数上限，不大于 0 时使用 DefaultSize
+	TTL      time.Duration // 缓存有效期，不大于 0 时使用 DefaultTTL
+	Dir      string        // 磁盘缓存目录，为空时仅使用内存缓存
+	DiskSize int           // 磁盘缓存条数上限，不大于 0 时不限制
+	Logger   *log.Logger   // 记录缓存命中与未命中，为空时使用 log.Default()
+}
+
+// Service 实现了 bazi.Service 接口，在下游排盘服务之前按请求缓存成功的排盘结果。
+// 业务错误（errcode 非 0）与调用错误不缓存。
+type Service struct {
+	next   bazi.Service
+	memory *lru
+	disk   *diskStore
+	ttl    time.Duration
+	logger *log.Logger
+	now    func() time.Time
+
+	hits, diskHits, misses atomic.Int64
+}
+
+// Stats 缓存统计
+type Stats struct {
+	Entries     int   // 内存中的缓存条数
+	Capacity    int   // 内存缓存条数上限
+	Disk        bool  // 是否启用磁盘缓存
+	DiskEntries int   // 磁盘缓存文件数
+	Hits        int64 // 内存命中次数
+	DiskHits    int64 // 磁盘命中次数
+	Misses      int64 // 未命中次数
+}
+
+// New 创建缓存装饰器，磁盘缓存目录无法创建时返回错误
+func New(next bazi.Service, opts Options) (*Service, error) {
+	if opts.Size <= 0 {
+		opts.Size = DefaultSize
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	s := &Service{
+		next:   next,
+		memory: newLRU(opts.Size),
+		ttl:    opts.TTL,
+		logger: opts.Logger,
+		now:    time.Now,
+	}
+	if s.logger == nil {
+		s.logger = log.Default()
+	}
+	if opts.Dir != "" {
+		disk, err := newDiskStore(opts.Dir, opts.DiskSize)
+		if err != nil {
+			return nil, err
+		}
+		s.disk = disk
+	}
+	return s, nil
+}
+
+// GetPaipanResult 优先返回缓存中未过期的排盘结果，未命中时调用下游服务并缓存成功的结果。
+func (s *Service) GetPaipanResult(ctx context.Context, req bazi.Request) (*bazi.PaipanResponse, error) {
+	key := Key(req)
+	now := s.now()
+
+	if resp, ok := s.memory.get(key, now); ok {
+		s.hits.Add(1)
+		s.logger.Printf("排盘缓存命中（内存）: %s", shortKey(key))
+		return clone(resp), nil
+	}
+	if s.disk != nil {
+		if resp, expires, ok := s.disk.get(key, now); ok {
+			s.diskHits.Add(1)
+			s.logger.Printf("排盘缓存命中（磁盘）: %s", shortKey(key))
+			s.memory.put(key, resp, expires)
+			return clone(resp), nil
+		}
+	}
+	s.misses.Add(1)
+	s.logger.Printf("排盘缓存未命中: %s", shortKey(key))
+
+	resp, err := s.next.GetPaipanResult(ctx, req)
+	if err != nil || resp == nil || resp.ErrCode != 0 {
+		return resp, err
+	}
+
+	expires := now.Add(s.ttl)
+	s.memory.put(key, clone(resp), expires)
+	if s.disk != nil {
+		if err := s.disk.put(key, resp, expires); err != nil {
+			s.logger.Printf("写入磁盘缓存失败: %v", err)
+		}
+	}
+	return resp, nil
+}
+
+// Stats 返回缓存条数与命中统计
+func (s *Service) Stats() Stats {
+	stats := Stats{
+		Entries:  s.memory.len(),
+		Capacity: s.memory.capacity,
+		Hits:     s.hits.Load(),
+		DiskHits: s.diskHits.Load(),
+		Misses:   s.misses.Load(),
+	}
+	if s.disk != nil {
+		stats.Disk = true
+		stats.DiskEntries = s.disk.len()
+	}
+	return stats
+}
+
+// Key 返回请求的缓存键：规范化后的请求的 SHA-256
+func Key(req bazi.Request) string {
+	sum := sha256.Sum256([]byte(Canonical(req)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Canonical 将请求规范化为稳定的字符串：去除首尾空白，补全提供方的默认值，
+// 忽略不影响排盘结果的字段（output），农历以外的请求忽略闰月标记。
+func Canonical(req bazi.Request) string {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = "求测者"
+	}
+	sect, zhen, lang := req.Sect, req.Zhen, strings.ToLower(strings.TrimSpace(req.Lang))
+	if sect == 0 {
+		sect = 1
+	}
+	if zhen == 0 {
+		zhen = 2
+	}
+	if lang == "" {
+		lang = "zh-cn"
+	}
+	leap := req.Leap && req.Type == 0
+
+	fields := []string{
+		keyVersion,
+		"name=" + name,
+		"sex=" + strconv.Itoa(req.Sex),
+		"type=" + strconv.Itoa(req.Type),
+		fmt.Sprintf("date=%04d-%02d-%02d", req.Year, req.Month, req.Day),
+		"leap=" + strconv.FormatBool(leap),
+		fmt.Sprintf("time=%02d:%02d", req.Hours, req.Minute),
+		"sect=" + strconv.Itoa(sect),
+		"zhen=" + strconv.Itoa(zhen),
+		"province=" + strings.TrimSpace(req.Province),
+		"city=" + strings.TrimSpace(req.City),
+		"longitude=" + formatFloat(req.Longitude),
+		"latitude=" + formatFloat(req.Latitude),
+		"timezone=" + strings.TrimSpace(req.Timezone),
+		"lang=" + lang,
+	}
+	return strings.Join(fields, "|")
+}
+
+// formatFloat 格式化可选的坐标，未填写时为空
+func formatFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// shortKey 返回日志中展示的缓存键前缀
+func shortKey(key string) string {
+	return key[:12]
+}
+
+// clone 复制响应的顶层结构，调用方改写响应字段时不影响缓存中的结果
+func clone(resp *bazi.PaipanResponse) *bazi.PaipanResponse {
+	c := *resp
+	return &c
+}
@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// countingService 记录调用次数的下游服务
+type countingService struct {
+	calls int
+	resp  *bazi.PaipanResponse
+	err   error
+}
+
+func (s *countingService) GetPaipanResult(ctx context.Context, req bazi.Request) (*bazi.PaipanResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	resp := *s.resp
+	resp.Data.BaseInfo.Name = req.Name
+	return &resp, nil
+}
+
+func newTestService(t *testing.T, next bazi.Service, opts Options) (*Service, *bytes.Buffer, *time.Time) {
+	t.Helper()
+	var logs bytes.Buffer
+	opts.Logger = log.New(&logs, "", 0)
+	s, err := New(next, opts)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	return s, &logs, &now
+}
+
+var testRequest = bazi.Request{Name: "张三", Sex: 0, Type: 1, Year: 2000, Month: 1, Day: 2, Hours: 3, Minute: 4}
+
+func TestServiceMemory(t *testing.T) {
+	next := &countingService{resp: &bazi.PaipanResponse{Notice: "ok"}}
+	s, logs, now := newTestService(t, next, Options{Size: 2, TTL: time.Hour})
+	ctx := context.Background()
+
+	for range 3 {
+		resp, err := s.GetPaipanResult(ctx, testRequest)
+		if err != nil || resp.Data.BaseInfo.Name != "张三" {
+			t.Fatalf("GetPaipanResult() = %+v, %v", resp, err)
+		}
+	}
+	if next.calls != 1 {
+		t.Errorf("相同请求应只调用一次下游服务, got %d", next.calls)
+	}
+	if strings.Count(logs.String(), "命中（内存）") != 2 || strings.Count(logs.String(), "未命中") != 1 {
+		t.Errorf("日志 = %s", logs.String())
+	}
+
+	// 过期后重新调用
+	*now = now.Add(time.Hour)
+	s.GetPaipanResult(ctx, testRequest)
+	if next.calls != 2 {
+		t.Errorf("过期后应重新调用下游服务, got %d", next.calls)
+	}
+
+	// 超出容量时淘汰最久未使用的项
+	for _, name := range []string{"李四", "王五"} {
+		req := testRequest
+		req.Name = name
+		s.GetPaipanResult(ctx, req)
+	}
+	if s.memory.len() != 2 {
+		t.Errorf("内存缓存条数 = %d, want 2", s.memory.len())
+	}
+	s.GetPaipanResult(ctx, testRequest)
+	if next.calls != 5 {
+		t.Errorf("被淘汰的请求应重新调用下游服务, got %d", next.calls)
+	}
+	if stats := s.Stats(); stats != (Stats{Entries: 2, Capacity: 2, Hits: 2, Misses: 5}) {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestServiceSkipsFailures(t *testing.T) {
+	ctx := context.Background()
+
+	next := &countingService{resp: &bazi.PaipanResponse{ErrCode: 1, ErrMsg: "参数错误"}}
+	s, _, _ := newTestService(t, next, Options{})
+	s.GetPaipanResult(ctx, testRequest)
+	s.GetPaipanResult(ctx, testRequest)
+	if next.calls != 2 {
+		t.Errorf("业务错误不应缓存, got %d calls", next.calls)
+	}
+
+	failing := &countingService{err: errors.New("网络错误")}
+	s, _, _ = newTestService(t, failing, Options{})
+	if _, err := s.GetPaipanResult(ctx, testRequest); err == nil {
+		t.Error("应返回下游服务的错误")
+	}
+	s.GetPaipanResult(ctx, testRequest)
+	if failing.calls != 2 {
+		t.Errorf("调用错误不应缓存, got %d calls", failing.calls)
+	}
+}
+
+func TestServiceDisk(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	next := &countingService{resp: &bazi.PaipanResponse{Notice: "ok"}}
+
+	s, _, _ := newTestService(t, next, Options{Dir: dir, DiskSize: 2})
+	s.GetPaipanResult(ctx, testRequest)
+
+	// 新实例（模拟进程重启）从磁盘读取
+	restarted, logs, now := newTestService(t, next, Options{Dir: dir, DiskSize: 2})
+	resp, err := restarted.GetPaipanResult(ctx, testRequest)
+	if err != nil || resp.Notice != "ok" || next.calls != 1 {
+		t.Fatalf("重启后应命中磁盘缓存: %+v, %v, calls %d", resp, err, next.calls)
+	}
+	if !strings.Contains(logs.String(), "命中（磁盘）") {
+		t.Errorf("日志 = %s", logs.String())
+	}
+	if stats := restarted.Stats(); !stats.Disk || stats.DiskEntries != 1 || stats.DiskHits != 1 || stats.Misses != 0 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+
+	// 磁盘缓存过期后删除文件
+	*now = now.Add(DefaultTTL)
+	expired, _, _ := newTestService(t, next, Options{Dir: dir})
+	expired.now = restarted.now
+	expired.GetPaipanResult(ctx, testRequest)
+	if next.calls != 2 {
+		t.Errorf("磁盘缓存过期后应重新调用下游服务, got %d", next.calls)
+	}
+
+	// 超出磁盘条数上限时删除最早的文件
+	for _, name := range []string{"李四", "王五", "赵六"} {
+		req := testRequest
+		req.Name = name
+		restarted.GetPaipanResult(ctx, req)
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 2 {
+		t.Errorf("磁盘缓存文件数 = %d, want 2", len(files))
+	}
+}
+
+func TestKey(t *testing.T) {
+	base := testRequest
+	same := base
+	same.Name = " 张三 "
+	same.Sect, same.Zhen, same.Lang = 1, 2, "zh-CN"
+	same.Output = bazi.OutputJSON
+	same.Leap = true // 公历请求忽略闰月标记
+	if Key(base) != Key(same) {
+		t.Errorf("规范化后相同的请求应得到相同的键:\n%s\n%s", Canonical(base), Canonical(same))
+	}
+
+	longitude, latitude := 116.4, 39.9
+	different := []func(*bazi.Request){
+		func(r *bazi.Request) { r.Minute = 5 },
+		func(r *bazi.Request) { r.Zhen = 1 },
+		func(r *bazi.Request) { r.City = "北京" },
+		func(r *bazi.Request) { r.Longitude, r.Latitude = &longitude, &latitude },
+		func(r *bazi.Request) { r.Type, r.Leap = 0, true },
+	}
+	for i, modify := range different {
+		req := base
+		modify(&req)
+		if Key(req) == Key(base) {
+			t.Errorf("第 %d 个请求应得到不同的键: %s", i, Canonical(req))
+		}
+	}
+}
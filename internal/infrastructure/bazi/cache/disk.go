@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// diskRecord 磁盘缓存文件的内容
+type diskRecord struct {
+	Expires  time.Time            `json:"expires"`
+	Response *bazi.PaipanResponse `json:"response"`
+}
+
+// diskStore 以目录存放的磁盘缓存，每个缓存键一个 JSON 文件，进程重启后仍然有效
+type diskStore struct {
+	mu       sync.Mutex
+	dir      string
+	capacity int // 文件数上限，不大于 0 时不限制
+}
+
+// newDiskStore 创建磁盘缓存，目录不存在时自动创建
+func newDiskStore(dir string, capacity int) (*diskStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建磁盘缓存目录失败: %w", err)
+	}
+	return &diskStore{dir: dir, capacity: capacity}, nil
+}
+
+// path 返回缓存键对应的文件路径
+func (d *diskStore) path(key string) string {
+	return filepath.Join(d.dir, key+".json")
+}
+
+// get 读取未过期的缓存项，过期或损坏的文件会被删除
+func (d *diskStore) get(key string, now time.Time) (*bazi.PaipanResponse, time.Time, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	var record diskRecord
+	if err := json.Unmarshal(data, &record); err != nil || record.Response == nil || !now.Before(record.Expires) {
+		os.Remove(d.path(key))
+		return nil, time.Time{}, false
+	}
+	return record.Response, record.Expires, true
+}
+
+// put 写入缓存项：先写临时文件再重命名，避免读到写了一半的文件；超出文件数上限时删除最早写入的文件
+func (d *diskStore) put(key string, resp *bazi.PaipanResponse, expires time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	data, err := json.Marshal(diskRecord{Expires: expires, Response: resp})
+	if err != nil {
+		return fmt.Errorf("编码缓存项失败: %w", err)
+	}
+	tmp, err := os.CreateTemp(d.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("创建缓存文件失败: %w", err)
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("写入缓存文件失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), d.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("写入缓存文件失败: %w", err)
+	}
+	return d.prune()
+}
+
+// len 返回磁盘缓存文件数（含已过期尚未删除的文件）
+func (d *diskStore) len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			count++
+		}
+	}
+	return count
+}
+
+// prune 文件数超出上限时按修改时间删除最早的缓存文件
+func (d *diskStore) prune() error {
+	if d.capacity <= 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return fmt.Errorf("读取磁盘缓存目录失败: %w", err)
+	}
+	type file struct {
+		name    string
+		modTime time.Time
+	}
+	var files []file
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, file{e.Name(), info.ModTime()})
+	}
+	if len(files) <= d.capacity {
+		return nil
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files[:len(files)-d.capacity] {
+		os.Remove(filepath.Join(d.dir, f.name))
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// entry 内存缓存中的一项
+type entry struct {
+	key     string
+	resp    *bazi.PaipanResponse
+	expires time.Time
+}
+
+// lru 并发安全的定长 LRU 缓存，超出容量时淘汰最久未使用的项，过期项在读取时删除
+type lru struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // 队首为最近使用
+	items    map[string]*list.Element
+}
+
+// newLRU 创建容量为 capacity 的 LRU 缓存
+func newLRU(capacity int) *lru {
+	return &lru{capacity: capacity, order: list.New(), items: make(map[string]*list.Element)}
+}
+
+// get 返回未过期的缓存项并将其标记为最近使用
+func (c *lru) get(key string, now time.Time) (*bazi.PaipanResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := elem.Value.(*entry)
+	if !now.Before(e.expires) {
+		c.order.Remove(elem)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return e.resp, true
+}
+
+// put 写入缓存项，超出容量时淘汰最久未使用的项
+func (c *lru) put(key string, resp *bazi.PaipanResponse, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		elem.Value = &entry{key: key, resp: resp, expires: expires}
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.order.PushFront(&entry{key: key, resp: resp, expires: expires})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry).key)
+	}
+}
+
+// len 返回缓存项数
+func (c *lru) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,177 @@
+// Package fake 提供模拟缘分居排盘接口（/v1/Bazi/paipan）的 HTTP 服务，排盘结果由本地排盘引擎计算，
+// 可配置业务错误码、HTTP 状态码、响应延迟与损坏的响应体，用于离线测试 APIClient 与完整的工具调用链路。
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+	"github.com/justinwongcn/bazi-mcp/internal/infrastructure/bazi/local"
+)
+
+// PaipanPath 排盘接口的路径，APIClient 的 Endpoint 为其上一级：/v1/Bazi
+const PaipanPath = "/v1/Bazi/paipan"
+
+// Behavior 一次请求的响应方式，零值表示按请求正常排盘
+type Behavior struct {
+	Status    int                  // HTTP 状态码，非 0 且不为 200 时只返回状态码与一段 HTML
+	ErrCode   int                  // 业务错误码，非 0 时返回 errcode 与 ErrMsg
+	ErrMsg    string               // 业务错误说明
+	Latency   time.Duration        // 响应前的延迟，客户端断开时提前结束
+	Malformed bool                 // 返回截断的、无法解析的 JSON
+	Response  *bazi.PaipanResponse // 固定的响应，为空时由本地排盘引擎按请求计算
+}
+
+// Handler 模拟排盘接口的 http.Handler，记录收到的请求
+type Handler struct {
+	engine *local.Engine
+
+	mu       sync.Mutex
+	behavior Behavior
+	queue    []Behavior
+	requests []url.Values
+}
+
+// NewHandler 创建模拟排盘接口的处理程序
+func NewHandler() *Handler {
+	return &Handler{engine: local.NewEngine()}
+}
+
+// SetBehavior 设置默认的响应方式
+func (h *Handler) SetBehavior(b Behavior) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.behavior = b
+}
+
+// Enqueue 依次为接下来的请求指定响应方式，用完后恢复默认的响应方式
+func (h *Handler) Enqueue(behaviors ...Behavior) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.queue = append(h.queue, behaviors...)
+}
+
+// Requests 返回已收到的请求表单
+func (h *Handler) Requests() []url.Values {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]url.Values(nil), h.requests...)
+}
+
+// next 记录请求并取出本次的响应方式
+func (h *Handler) next(form url.Values) Behavior {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.requests = append(h.requests, form)
+	if len(h.queue) > 0 {
+		b := h.queue[0]
+		h.queue = h.queue[1:]
+		return b
+	}
+	return h.behavior
+}
+
+// ServeHTTP 按当前的响应方式响应排盘请求
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != PaipanPath {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	b := h.next(r.PostForm)
+
+	if b.Latency > 0 {
+		timer := time.NewTimer(b.Latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if b.Status != 0 && b.Status != http.StatusOK {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(b.Status)
+		fmt.Fprintf(w, "<html><body><h1>%d %s</h1></body></html>", b.Status, http.StatusText(b.Status))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if b.Malformed {
+		fmt.Fprint(w, `{"errcode":0,"errmsg":"请求成功","data":{"base_info":{"name":`)
+		return
+	}
+	if b.ErrCode != 0 {
+		json.NewEncoder(w).Encode(bazi.PaipanResponse{ErrCode: b.ErrCode, ErrMsg: b.ErrMsg})
+		return
+	}
+
+	resp := b.Response
+	if resp == nil {
+		var err error
+		if resp, err = h.paipan(r.Context(), r.PostForm); err != nil {
+			json.NewEncoder(w).Encode(bazi.PaipanResponse{ErrCode: 1, ErrMsg: err.Error()})
+			return
+		}
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// paipan 将请求表单还原为排盘请求并由本地排盘引擎计算结果
+func (h *Handler) paipan(ctx context.Context, form url.Values) (*bazi.PaipanResponse, error) {
+	if form.Get("api_key") == "" {
+		return nil, fmt.Errorf("api_key 不能为空")
+	}
+	req := bazi.Request{
+		Name:     form.Get("name"),
+		Province: form.Get("province"),
+		City:     form.Get("city"),
+		Lang:     form.Get("lang"),
+	}
+	fields := map[string]*int{
+		"sex": &req.Sex, "type": &req.Type, "year": &req.Year, "month": &req.Month, "day": &req.Day,
+		"hours": &req.Hours, "minute": &req.Minute, "zhen": &req.Zhen, "sect": &req.Sect,
+	}
+	for name, field := range fields {
+		v, err := strconv.Atoi(form.Get(name))
+		if err != nil {
+			return nil, fmt.Errorf("参数 %s 无效: %q", name, form.Get(name))
+		}
+		*field = v
+	}
+	return h.engine.GetPaipanResult(ctx, req)
+}
+
+// Server 运行模拟排盘接口的 httptest 服务器
+type Server struct {
+	*Handler
+	*httptest.Server
+}
+
+// NewServer 启动模拟排盘接口，使用完毕后需调用 Close
+func NewServer() *Server {
+	h := NewHandler()
+	return &Server{Handler: h, Server: httptest.NewServer(h)}
+}
+
+// Endpoint 返回 APIClient 使用的接口地址
+func (s *Server) Endpoint() string {
+	return s.URL + "/v1/Bazi"
+}
@@ -0,0 +1,123 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/application"
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+	baziInfra "github.com/justinwongcn/bazi-mcp/internal/infrastructure/bazi"
+	"github.com/justinwongcn/bazi-mcp/internal/infrastructure/bazi/fixture"
+)
+
+var testRequest = bazi.Request{Name: "张三", Sex: 0, Type: 1, Year: 2000, Month: 1, Day: 2, Hours: 3, Minute: 4}
+
+// newTestClient 创建指向模拟接口的客户端，重试等待缩短为 1 毫秒
+func newTestClient(t *testing.T, opts baziInfra.ClientOptions) (*baziInfra.APIClient, *Server) {
+	t.Helper()
+	server := NewServer()
+	t.Cleanup(server.Close)
+	opts.Endpoint = server.Endpoint()
+	opts.APIKey = "test"
+	opts.BaseBackoff = time.Millisecond
+	opts.MaxBackoff = time.Millisecond
+	return baziInfra.NewAPIClient(opts), server
+}
+
+func TestServerBehaviors(t *testing.T) {
+	tests := []struct {
+		name     string
+		behavior Behavior
+		kind     bazi.ErrorKind
+		calls    int
+	}{
+		{"业务错误", Behavior{ErrCode: 10001, ErrMsg: "出生年份超出范围"}, bazi.ErrorBusiness, 1},
+		{"服务不可用", Behavior{Status: http.StatusServiceUnavailable}, bazi.ErrorHTTPStatus, 3},
+		{"响应体损坏", Behavior{Malformed: true}, bazi.ErrorDecode, 1},
+		{"响应超时", Behavior{Latency: time.Second}, bazi.ErrorNetwork, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, server := newTestClient(t, baziInfra.ClientOptions{Timeout: 50 * time.Millisecond})
+			server.SetBehavior(tt.behavior)
+
+			_, err := c.GetPaipanResult(context.Background(), testRequest)
+			var providerErr *bazi.ProviderError
+			if !errors.As(err, &providerErr) || providerErr.Kind != tt.kind {
+				t.Fatalf("GetPaipanResult() error = %v，期望 %s", err, tt.kind)
+			}
+			if got := len(server.Requests()); got != tt.calls {
+				t.Errorf("请求次数 = %d，期望 %d", got, tt.calls)
+			}
+		})
+	}
+}
+
+func TestServerEnqueue(t *testing.T) {
+	c, server := newTestClient(t, baziInfra.ClientOptions{})
+	server.Enqueue(Behavior{Status: http.StatusBadGateway}, Behavior{Status: http.StatusTooManyRequests})
+
+	resp, err := c.GetPaipanResult(context.Background(), testRequest)
+	if err != nil {
+		t.Fatalf("GetPaipanResult() error = %v", err)
+	}
+	if got := resp.Data.BaziInfo.Bazi; len(got) != 4 || got[2] != "己未" {
+		t.Errorf("四柱 = %q", got)
+	}
+	requests := server.Requests()
+	if len(requests) != 3 || requests[2].Get("year") != "2000" || requests[2].Get("api_key") != "test" {
+		t.Errorf("请求 = %v", requests)
+	}
+}
+
+// TestRecordReplay 经模拟接口完成排盘并录制，再由录制结果离线回放，两次的排盘文本应一致
+func TestRecordReplay(t *testing.T) {
+	c, server := newTestClient(t, baziInfra.ClientOptions{})
+	dir := t.TempDir()
+	recorder, err := fixture.NewRecorder(c, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorded, err := application.NewBaziAppService(recorder, nil, nil).Paipan(context.Background(), testRequest)
+	if err != nil || recorded.IsError {
+		t.Fatalf("Paipan() = %+v, %v", recorded, err)
+	}
+	if !strings.Contains(recorded.Text, "【八字排盘】") || recorded.Chart.Pillars.Day.Ganzhi != "己未" {
+		t.Fatalf("排盘文本 = %s", recorded.Text)
+	}
+
+	server.Close()
+	replayer, err := fixture.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := application.NewBaziAppService(replayer, nil, nil).Paipan(context.Background(), testRequest)
+	if err != nil || replayed.IsError {
+		t.Fatalf("回放 Paipan() = %+v, %v", replayed, err)
+	}
+	if replayed.Text != recorded.Text {
+		t.Errorf("回放结果与录制时不一致:\n%s\n---\n%s", replayed.Text, recorded.Text)
+	}
+
+	// 业务错误不录制
+	server = NewServer()
+	defer server.Close()
+	server.SetBehavior(Behavior{ErrCode: 1, ErrMsg: "参数错误"})
+	failing, err := fixture.NewRecorder(baziInfra.NewAPIClient(baziInfra.ClientOptions{Endpoint: server.Endpoint(), APIKey: "test"}), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := testRequest
+	other.Year = 2001
+	if _, err := failing.GetPaipanResult(context.Background(), other); err == nil {
+		t.Fatal("业务错误应返回错误")
+	}
+	if _, err := replayer.GetPaipanResult(context.Background(), other); !errors.Is(err, fixture.ErrNoFixture) {
+		t.Errorf("业务错误不应录制, error = %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+// Package fixture 提供基于录制结果的排盘提供方：Recorder 将真实提供方的成功响应按规范化后的请求保存到目录中，
+// Provider 从目录中回放，用于离线演示、测试以及作为其他提供方不可用时的回退。
+package fixture
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+	"github.com/justinwongcn/bazi-mcp/internal/infrastructure/bazi/cache"
+)
+
+// ErrNoFixture 没有与请求对应的录制结果
+var ErrNoFixture = errors.New("没有对应的录制结果")
+
+// Provider 实现了 bazi.Service 接口，从目录中读取以请求缓存键命名的 JSON 文件（<key>.json），
+// 文件内容为提供方的原始响应 bazi.PaipanResponse。
+type Provider struct {
+	dir string
+}
+
+// New 创建录制结果提供方，目录不存在时返回错误
+func New(dir string) (*Provider, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取录制结果目录失败: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("录制结果路径不是目录: %s", dir)
+	}
+	return &Provider{dir: dir}, nil
+}
+
+// Path 返回请求对应的录制结果文件路径
+func (p *Provider) Path(req bazi.Request) string {
+	return fixturePath(p.dir, req)
+}
+
+// fixturePath 返回目录中请求对应的录制结果文件路径：<缓存键>.json
+func fixturePath(dir string, req bazi.Request) string {
+	return filepath.Join(dir, cache.Key(req)+".json")
+}
+
+// GetPaipanResult 返回请求对应的录制结果，未录制时返回 ErrNoFixture
+func (p *Provider) GetPaipanResult(ctx context.Context, req bazi.Request) (*bazi.PaipanResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	path := p.Path(req)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNoFixture, filepath.Base(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取录制结果失败: %w", err)
+	}
+	var resp bazi.PaipanResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("解析录制结果 %s 失败: %w", filepath.Base(path), err)
+	}
+	return &resp, nil
+}
+
+// CheckHealth 检查录制结果目录是否可读，并报告已录制的结果数
+func (p *Provider) CheckHealth(ctx context.Context) bazi.ProviderStatus {
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return bazi.ProviderStatus{Detail: fmt.Sprintf("读取录制结果目录失败: %v", err)}
+	}
+	count := 0
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
+			count++
+		}
+	}
+	return bazi.ProviderStatus{Reachable: true, Detail: fmt.Sprintf("%d 条录制结果", count)}
+}
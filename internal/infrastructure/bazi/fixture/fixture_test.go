@@ -0,0 +1,48 @@
+package fixture
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+func TestProvider(t *testing.T) {
+	dir := t.TempDir()
+	p, err := New(dir)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	req := bazi.Request{Name: "张三", Sex: 0, Type: 1, Year: 2000, Month: 1, Day: 2, Hours: 3, Minute: 4}
+
+	if _, err := p.GetPaipanResult(context.Background(), req); !errors.Is(err, ErrNoFixture) {
+		t.Fatalf("未录制的请求 error = %v", err)
+	}
+
+	if err := os.WriteFile(p.Path(req), []byte(`{"errcode":0,"errmsg":"","data":{"base_info":{"name":"张三"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// 输出格式不影响排盘结果，应命中同一份录制结果
+	req.Output = bazi.OutputJSON
+	resp, err := p.GetPaipanResult(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetPaipanResult() error = %v", err)
+	}
+	if resp.Data.BaseInfo.Name != "张三" {
+		t.Errorf("录制结果 = %+v", resp.Data.BaseInfo)
+	}
+
+	if err := os.WriteFile(p.Path(req), []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.GetPaipanResult(context.Background(), req); err == nil || errors.Is(err, ErrNoFixture) {
+		t.Errorf("录制结果损坏 error = %v", err)
+	}
+
+	if _, err := New(filepath.Join(dir, "missing")); err == nil {
+		t.Error("目录不存在时应返回错误")
+	}
+}
@@ -0,0 +1,80 @@
+package fixture
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// Recorder 实现了 bazi.Service 接口，调用下游提供方并将成功的响应保存为录制结果，供 Provider 回放。
+// 业务错误与调用错误不录制；保存失败只记录日志，不影响本次排盘。
+type Recorder struct {
+	next   bazi.Service
+	dir    string
+	logger *log.Logger
+}
+
+// NewRecorder 创建录制装饰器，目录不存在时自动创建
+func NewRecorder(next bazi.Service, dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建录制结果目录失败: %w", err)
+	}
+	return &Recorder{next: next, dir: dir, logger: log.Default()}, nil
+}
+
+// GetPaipanResult 调用下游提供方，成功时保存响应
+func (r *Recorder) GetPaipanResult(ctx context.Context, req bazi.Request) (*bazi.PaipanResponse, error) {
+	resp, err := r.next.GetPaipanResult(ctx, req)
+	if err != nil || resp == nil || resp.ErrCode != 0 {
+		return resp, err
+	}
+	path := fixturePath(r.dir, req)
+	if err := writeFixture(path, resp); err != nil {
+		r.logger.Printf("保存录制结果失败: %v", err)
+	} else {
+		r.logger.Printf("已录制排盘结果: %s", filepath.Base(path))
+	}
+	return resp, nil
+}
+
+// CheckHealth 报告下游提供方的状态，下游不支持探测时视为可用
+func (r *Recorder) CheckHealth(ctx context.Context) bazi.ProviderStatus {
+	status := bazi.ProviderStatus{Reachable: true}
+	if checker, ok := r.next.(bazi.HealthChecker); ok {
+		status = checker.CheckHealth(ctx)
+	}
+	if status.Detail != "" {
+		status.Detail += "；"
+	}
+	status.Detail += "录制到 " + r.dir
+	return status
+}
+
+// writeFixture 先写入临时文件再重命名，避免回放时读到写了一半的文件
+func writeFixture(path string, resp *bazi.PaipanResponse) error {
+	recorded := *resp
+	recorded.Provider = ""
+	data, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".fixture-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
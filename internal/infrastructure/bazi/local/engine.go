@@ -0,0 +1,367 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+	"github.com/justinwongcn/bazi-mcp/internal/domain/calendar"
+)
+
+const (
+	dayunCount    = 12 // 排出的大运步数
+	liuNianCount  = 10 // 每步大运的流年数
+	defaultName   = "求测者"
+	successNotice = "本结果由本地排盘引擎计算，仅供娱乐使用，请勿用于封建迷信和违法用途。"
+)
+
+// 2000年1月1日为戊午日，作为日柱推算基准
+var dayPillarEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const dayPillarEpochIndex = 54
+
+// 星座名称及各月换座日期
+var (
+	constellations   = []string{"摩羯座", "水瓶座", "双鱼座", "白羊座", "金牛座", "双子座", "巨蟹座", "狮子座", "处女座", "天秤座", "天蝎座", "射手座", "摩羯座"}
+	constellationDay = []int{20, 19, 21, 20, 21, 22, 23, 23, 23, 24, 23, 22}
+)
+
+// Engine 实现了 bazi.Service 接口，在本地完成四柱、藏干、十神、长生、纳音、空亡及大运的计算，无需访问外部 API。
+type Engine struct {
+	location *time.Location
+}
+
+// NewEngine 创建一个新的本地排盘引擎，出生时间按北京时间（UTC+8）解释。
+func NewEngine() *Engine {
+	return &Engine{
+		location: time.FixedZone("CST", 8*3600),
+	}
+}
+
+// GetPaipanResult 在本地计算八字排盘结果，输入错误以业务错误码的形式返回。
+func (e *Engine) GetPaipanResult(ctx context.Context, req bazi.Request) (*bazi.PaipanResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if errMsg := e.validate(req); errMsg != "" {
+		return &bazi.PaipanResponse{ErrCode: 1, ErrMsg: errMsg}, nil
+	}
+	if req.Type == 0 {
+		solar, err := calendar.LunarToSolar(req.Year, req.Month, req.Day, req.Leap)
+		if err != nil {
+			return &bazi.PaipanResponse{ErrCode: 1, ErrMsg: fmt.Sprintf("农历出生日期无效: %v", err)}, nil
+		}
+		req.Type = 1
+		req.Year, req.Month, req.Day = solar.Year(), int(solar.Month()), solar.Day()
+	}
+
+	birth := time.Date(req.Year, time.Month(req.Month), req.Day, req.Hours, req.Minute, 0, 0, e.location)
+	c := newChart(birth, req.Sect)
+
+	return &bazi.PaipanResponse{
+		ErrCode: 0,
+		ErrMsg:  "请求成功",
+		Notice:  successNotice,
+		Data:    c.data(req),
+	}, nil
+}
+
+// CheckHealth 本地引擎无需外部依赖，始终可用
+func (e *Engine) CheckHealth(ctx context.Context) bazi.ProviderStatus {
+	return bazi.ProviderStatus{Reachable: true, Detail: "本地计算"}
+}
+
+// validate 校验本地引擎支持的输入范围
+func (e *Engine) validate(req bazi.Request) string {
+	if req.Type != 0 && req.Type != 1 {
+		return "历法类型无效，仅支持 0（农历）或 1（公历）"
+	}
+	if req.Zhen == 1 {
+		return "本地排盘引擎暂不支持真太阳时计算，请设置 zhen=2"
+	}
+	if req.Sex != 0 && req.Sex != 1 {
+		return "性别参数无效，仅支持 0（男）或 1（女）"
+	}
+	if req.Year < calendar.MinYear || req.Year > calendar.MaxYear {
+		return fmt.Sprintf("出生年份超出本地排盘引擎支持范围（%d-%d）", calendar.MinYear, calendar.MaxYear)
+	}
+	if req.Hours < 0 || req.Hours > 23 || req.Minute < 0 || req.Minute > 59 {
+		return "出生时间无效，小时应为 0-23，分钟应为 0-59"
+	}
+	if req.Type == 0 {
+		// 农历日期由 calendar.LunarToSolar 校验
+		return ""
+	}
+	date := time.Date(req.Year, time.Month(req.Month), req.Day, 0, 0, 0, 0, time.UTC)
+	if date.Year() != req.Year || int(date.Month()) != req.Month || date.Day() != req.Day {
+		return fmt.Sprintf("出生日期无效: %d年%d月%d日", req.Year, req.Month, req.Day)
+	}
+	return ""
+}
+
+// chart 表示本地计算得到的命盘
+type chart struct {
+	birth   time.Time
+	pillars [4]int // 年月日时四柱的六十甲子序号
+	monthNo int    // 月令序号，0 为寅月
+}
+
+// newChart 根据北京时间出生时刻排出四柱
+func newChart(birth time.Time, sect int) *chart {
+	c := &chart{birth: birth}
+
+	// 年柱以立春为界
+	year := birth.Year()
+	if birth.Before(calendar.SolarTermTime(year, calendar.LiChun)) {
+		year--
+	}
+	c.pillars[0] = ((year-4)%60 + 60) % 60
+
+	// 月柱以节为界，月干由年干按五虎遁推出
+	c.monthNo = (int(calendar.PrevJie(birth).Term) + 22) % 24 / 2
+	monthStem := (c.stem(0)%5*2 + 2 + c.monthNo) % 10
+	c.pillars[1] = bazi.JiaZiIndex(monthStem, (c.monthNo+2)%12)
+
+	// 日柱按公历日期推算，晚子时（23 时）依流派决定是否换日
+	civil := time.Date(birth.Year(), birth.Month(), birth.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(civil.Sub(dayPillarEpoch).Hours() / 24)
+	dayIndex := ((dayPillarEpochIndex+days)%60 + 60) % 60
+	hourDayIndex := dayIndex
+	if birth.Hour() == 23 {
+		hourDayIndex = (dayIndex + 1) % 60
+		if sect != 2 {
+			dayIndex = hourDayIndex
+		}
+	}
+	c.pillars[2] = dayIndex
+
+	// 时柱由日干按五鼠遁推出
+	hourBranch := (birth.Hour() + 1) / 2 % 12
+	hourStem := (hourDayIndex%10%5*2 + hourBranch) % 10
+	c.pillars[3] = bazi.JiaZiIndex(hourStem, hourBranch)
+
+	return c
+}
+
+func (c *chart) stem(i int) int {
+	return c.pillars[i] % 10
+}
+
+func (c *chart) branch(i int) int {
+	return c.pillars[i] % 12
+}
+
+// tenGod 返回天干相对日主的十神
+func (c *chart) tenGod(stem int) string {
+	return bazi.TenGod(c.stem(2), stem)
+}
+
+// forward 判断大运是否顺排：阳年男命、阴年女命顺排
+func (c *chart) forward(sex int) bool {
+	return bazi.IsYangStem(c.stem(0)) == (sex == 0)
+}
+
+// data 组装与外部 API 相同结构的排盘数据
+func (c *chart) data(req bazi.Request) bazi.Data {
+	name := req.Name
+	if name == "" {
+		name = defaultName
+	}
+	sex := "乾造"
+	if req.Sex == 1 {
+		sex = "坤造"
+	}
+
+	data := bazi.Data{
+		BaseInfo: bazi.BaseInfo{
+			Sex:     sex,
+			Name:    name,
+			Gongli:  fmt.Sprintf("%d年%d月%d日%d时%d分", req.Year, req.Month, req.Day, req.Hours, req.Minute),
+			Nongli:  c.nongli(),
+			Zhengge: c.zhengge(),
+		},
+		BaziInfo:   c.baziInfo(),
+		DetailInfo: c.detailInfo(),
+		StartInfo: bazi.StartInfo{
+			Xz: constellation(c.birth),
+			Sx: bazi.ShengXiao[c.branch(0)],
+		},
+	}
+	data.StartInfo.Jishen = []string{
+		data.DetailInfo.Shensha.Year,
+		data.DetailInfo.Shensha.Month,
+		data.DetailInfo.Shensha.Day,
+		data.DetailInfo.Shensha.Hour,
+	}
+
+	c.fillDayun(&data, req.Sex)
+	return data
+}
+
+// baziInfo 计算四柱的干支、十神、藏干、长生、纳音
+func (c *chart) baziInfo() bazi.BaziInfo {
+	info := bazi.BaziInfo{Kw: bazi.KongWang(c.pillars[2])}
+	for i, index := range c.pillars {
+		godText := c.tenGod(c.stem(i))
+		if i == 2 {
+			godText = "日元"
+		}
+		hidden, hiddenGods := c.hidden(c.branch(i))
+
+		info.Bazi = append(info.Bazi, bazi.JiaZi(index))
+		info.TgCgGod = append(info.TgCgGod, godText)
+		info.DzCg = append(info.DzCg, strings.Join(hidden, "|"))
+		info.DzCgGod = append(info.DzCgGod, strings.Join(hiddenGods, "|"))
+		info.DayCs = append(info.DayCs, bazi.ChangSheng[bazi.ChangShengIndex(c.stem(2), c.branch(i))])
+		info.NaYin = append(info.NaYin, bazi.NaYinOf(index))
+	}
+	return info
+}
+
+// hidden 返回地支藏干及其十神（按本气中气余气排序）
+func (c *chart) hidden(branch int) (stems, gods []string) {
+	for _, s := range bazi.HiddenStems(branch) {
+		stems = append(stems, bazi.TianGan[s])
+		gods = append(gods, c.tenGod(s))
+	}
+	return stems, gods
+}
+
+// detailInfo 按柱位组织详细信息
+func (c *chart) detailInfo() bazi.DetailInfo {
+	var (
+		detail  bazi.DetailInfo
+		zhuxing [4]string
+		xingyun [4]string
+		zizuo   [4]string
+		kong    [4]string
+		nayin   [4]string
+		shensha [4]string
+		canggan [4][]string
+		fuxing  [4][]string
+		sizhu   [4][2]string
+	)
+	for i, index := range c.pillars {
+		zhuxing[i] = c.tenGod(c.stem(i))
+		if i == 2 {
+			zhuxing[i] = "日元"
+		}
+		xingyun[i] = bazi.ChangSheng[bazi.ChangShengIndex(c.stem(2), c.branch(i))]
+		zizuo[i] = bazi.ChangSheng[bazi.ChangShengIndex(c.stem(i), c.branch(i))]
+		kong[i] = bazi.KongWang(index)
+		nayin[i] = bazi.NaYinOf(index)
+		shensha[i] = c.shensha(c.branch(i))
+		canggan[i], fuxing[i] = c.hidden(c.branch(i))
+		sizhu[i] = [2]string{bazi.TianGan[c.stem(i)], bazi.DiZhi[c.branch(i)]}
+	}
+
+	detail.Zhuxing = bazi.ZhuxingInfo{Year: zhuxing[0], Month: zhuxing[1], Day: zhuxing[2], Hour: zhuxing[3]}
+	detail.Sizhu = bazi.SizhuInfo{
+		Year:  bazi.YearInfo{Tg: sizhu[0][0], Dz: sizhu[0][1]},
+		Month: bazi.MonthInfo{Tg: sizhu[1][0], Dz: sizhu[1][1]},
+		Day:   bazi.DayInfo{Tg: sizhu[2][0], Dz: sizhu[2][1]},
+		Hour:  bazi.HourInfo{Tg: sizhu[3][0], Dz: sizhu[3][1]},
+	}
+	detail.Canggan = bazi.CangganInfo{Year: canggan[0], Month: canggan[1], Day: canggan[2], Hour: canggan[3]}
+	detail.Fuxing = bazi.FuxingInfo{Year: fuxing[0], Month: fuxing[1], Day: fuxing[2], Hour: fuxing[3]}
+	detail.Xingyun = bazi.XingyunInfo{Year: xingyun[0], Month: xingyun[1], Day: xingyun[2], Hour: xingyun[3]}
+	detail.Zizuo = bazi.ZizuoInfo{Year: zizuo[0], Month: zizuo[1], Day: zizuo[2], Hour: zizuo[3]}
+	detail.Kongwang = bazi.KongwangInfo{Year: kong[0], Month: kong[1], Day: kong[2], Hour: kong[3]}
+	detail.Nayin = bazi.NayinInfo{Year: nayin[0], Month: nayin[1], Day: nayin[2], Hour: nayin[3]}
+	detail.Shensha = bazi.ShenshaInfo{Year: shensha[0], Month: shensha[1], Day: shensha[2], Hour: shensha[3]}
+	return detail
+}
+
+// nongli 返回农历日期描述，如“己卯年 十一月 廿六日 寅时”；农历年以正月初一为界，与以立春为界的年柱可能不同
+func (c *chart) nongli() string {
+	lunar, err := calendar.SolarToLunar(c.birth.Year(), int(c.birth.Month()), c.birth.Day())
+	if err != nil {
+		return fmt.Sprintf("%s年 %s时", bazi.JiaZi(c.pillars[0]), bazi.DiZhi[c.branch(3)])
+	}
+	yearIndex := ((lunar.Year-4)%60 + 60) % 60
+	return fmt.Sprintf("%s年 %s %s日 %s时", bazi.JiaZi(yearIndex), lunar.MonthName(), lunar.DayName(), bazi.DiZhi[c.branch(3)])
+}
+
+// zhengge 按月令本气十神定格，月令为比劫时取建禄格或月刃格
+func (c *chart) zhengge() string {
+	god := c.tenGod(bazi.HiddenStems(c.branch(1))[0])
+	switch god {
+	case "比肩":
+		return "建禄格"
+	case "劫财":
+		return "月刃格"
+	default:
+		return god + "格"
+	}
+}
+
+// fillDayun 计算起运、交运时间及大运流年
+func (c *chart) fillDayun(data *bazi.Data, sex int) {
+	forward := c.forward(sex)
+
+	// 顺排取出生后的下一个节，逆排取出生前的上一个节
+	jie := calendar.PrevJie(c.birth)
+	if forward {
+		jie = calendar.NextJie(c.birth)
+	}
+
+	// 三天折合一年，一天折合四个月，一个时辰折合十天
+	span := c.birth.Sub(jie.Time)
+	if span < 0 {
+		span = -span
+	}
+	seconds := int64(span.Seconds())
+	years := seconds / (3 * 86400)
+	seconds %= 3 * 86400
+	months := seconds / (6 * 3600)
+	seconds %= 6 * 3600
+	days := seconds / (12 * 60)
+	seconds %= 12 * 60
+
+	jiaoyun := c.birth.AddDate(int(years), int(months), int(days)).Add(time.Duration(seconds*120) * time.Second)
+	data.BaseInfo.Qiyun = fmt.Sprintf("%d年%d月%d天起运", years, months, days)
+	data.BaseInfo.Jiaoyun = fmt.Sprintf("%d年%d月%d日%d时%d分%d秒",
+		jiaoyun.Year(), jiaoyun.Month(), jiaoyun.Day(), jiaoyun.Hour(), jiaoyun.Minute(), jiaoyun.Second())
+
+	info := &data.DayunInfo
+	yearsInfo := []*[]bazi.YearChar{
+		&info.YearsInfo0, &info.YearsInfo1, &info.YearsInfo2, &info.YearsInfo3, &info.YearsInfo4,
+		&info.YearsInfo5, &info.YearsInfo6, &info.YearsInfo7, &info.YearsInfo8, &info.YearsInfo9,
+	}
+	step := 1
+	if !forward {
+		step = -1
+	}
+	for i := range dayunCount {
+		index := ((c.pillars[1]+step*(i+1))%60 + 60) % 60
+		stem, branch := index%10, index%12
+		startYear := jiaoyun.Year() + 10*i
+
+		info.Big = append(info.Big, bazi.JiaZi(index))
+		info.BigGod = append(info.BigGod, c.tenGod(stem))
+		info.BigCs = append(info.BigCs, bazi.ChangSheng[bazi.ChangShengIndex(c.stem(2), branch)])
+		info.XuSui = append(info.XuSui, startYear-c.birth.Year()+1)
+		info.BigStartYear = append(info.BigStartYear, startYear)
+		info.BigEndYear = append(info.BigEndYear, startYear+liuNianCount-1)
+		for k := range liuNianCount {
+			*yearsInfo[k] = append(*yearsInfo[k], bazi.YearChar{YearChar: bazi.JiaZi(startYear + k - 1 - 4)})
+		}
+
+		data.DetailInfo.Dayunshensha = append(data.DetailInfo.Dayunshensha, bazi.DayunShensha{
+			Tgdz:    bazi.JiaZi(index),
+			Shensha: c.shensha(branch),
+		})
+	}
+}
+
+// constellation 返回公历日期对应的星座
+func constellation(t time.Time) string {
+	month := int(t.Month())
+	if t.Day() >= constellationDay[month-1] {
+		return constellations[month]
+	}
+	return constellations[month-1]
+}
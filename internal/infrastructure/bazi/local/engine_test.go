@@ -0,0 +1,150 @@
+package local
+
+import (
+	"context"
+	"testing"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+func TestGetPaipanResult(t *testing.T) {
+	engine := NewEngine()
+
+	// 2000年1月2日3时4分 男命，与 application/testdata/result.json 中的 API 结果对照
+	resp, err := engine.GetPaipanResult(context.Background(), bazi.Request{
+		Name: "张三", Sex: 0, Type: 1, Year: 2000, Month: 1, Day: 2, Hours: 3, Minute: 4, Sect: 1, Zhen: 2,
+	})
+	if err != nil {
+		t.Fatalf("GetPaipanResult() error = %v", err)
+	}
+	if resp.ErrCode != 0 {
+		t.Fatalf("GetPaipanResult() errcode = %d, errmsg = %s", resp.ErrCode, resp.ErrMsg)
+	}
+
+	info := resp.Data.BaziInfo
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"四柱", info.Bazi, []string{"己卯", "丙子", "己未", "丙寅"}},
+		{"天干十神", info.TgCgGod, []string{"比肩", "正印", "日元", "正印"}},
+		{"地支藏干", info.DzCg, []string{"乙", "癸", "己|丁|乙", "甲|丙|戊"}},
+		{"藏干十神", info.DzCgGod, []string{"七杀", "偏财", "比肩|偏印|七杀", "正官|正印|劫财"}},
+		{"十二长生", info.DayCs, []string{"病", "绝", "冠带", "死"}},
+		{"纳音", info.NaYin, []string{"城头土", "涧下水", "天上火", "炉中火"}},
+		{"大运", resp.Data.DayunInfo.Big[:4], []string{"乙亥", "甲戌", "癸酉", "壬申"}},
+		{"大运十神", resp.Data.DayunInfo.BigGod[:4], []string{"七杀", "正官", "偏财", "正财"}},
+		{"大运长生", resp.Data.DayunInfo.BigCs[:4], []string{"胎", "养", "长生", "沐浴"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.got) != len(tt.want) {
+				t.Fatalf("%s = %v, want %v", tt.name, tt.got, tt.want)
+			}
+			for i := range tt.want {
+				if tt.got[i] != tt.want[i] {
+					t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+	if info.Kw != "子丑" {
+		t.Errorf("空亡 = %s, want 子丑", info.Kw)
+	}
+	if resp.Data.BaseInfo.Zhengge != "偏财格" {
+		t.Errorf("正格 = %s, want 偏财格", resp.Data.BaseInfo.Zhengge)
+	}
+	if got := resp.Data.DetailInfo.Kongwang.Year; got != "申酉" {
+		t.Errorf("年柱空亡 = %s, want 申酉", got)
+	}
+	if got := resp.Data.DayunInfo.YearsInfo1[0].YearChar; got != "戊子" {
+		t.Errorf("第一步大运首个流年 = %s, want 戊子", got)
+	}
+	if _, err := bazi.NewDayunPeriods(resp.Data.DayunInfo, resp.Data.DetailInfo.Dayunshensha); err != nil {
+		t.Errorf("大运信息应能规范化: %v", err)
+	}
+	if got := resp.Data.BaseInfo.Nongli; got != "己卯年 十一月 廿六日 寅时" {
+		t.Errorf("农历 = %s, want 己卯年 十一月 廿六日 寅时", got)
+	}
+	if resp.Data.StartInfo.Xz != "摩羯座" || resp.Data.StartInfo.Sx != "兔" {
+		t.Errorf("星座生肖 = %s %s, want 摩羯座 兔", resp.Data.StartInfo.Xz, resp.Data.StartInfo.Sx)
+	}
+}
+
+func TestNewChartBoundaries(t *testing.T) {
+	engine := NewEngine()
+	tests := []struct {
+		name string
+		req  bazi.Request
+		want []string
+	}{
+		// 2024年立春为2月4日16时27分
+		{"立春前", bazi.Request{Type: 1, Year: 2024, Month: 2, Day: 4, Hours: 16, Minute: 0}, []string{"癸卯", "乙丑", "戊戌", "庚申"}},
+		{"立春后", bazi.Request{Type: 1, Year: 2024, Month: 2, Day: 4, Hours: 17, Minute: 0}, []string{"甲辰", "丙寅", "戊戌", "辛酉"}},
+		{"晚子时算明天", bazi.Request{Type: 1, Year: 2024, Month: 2, Day: 4, Hours: 23, Minute: 30, Sect: 1}, []string{"甲辰", "丙寅", "己亥", "甲子"}},
+		{"晚子时算当天", bazi.Request{Type: 1, Year: 2024, Month: 2, Day: 4, Hours: 23, Minute: 30, Sect: 2}, []string{"甲辰", "丙寅", "戊戌", "甲子"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := engine.GetPaipanResult(context.Background(), tt.req)
+			if err != nil || resp.ErrCode != 0 {
+				t.Fatalf("GetPaipanResult() err = %v, resp = %+v", err, resp)
+			}
+			for i, want := range tt.want {
+				if got := resp.Data.BaziInfo.Bazi[i]; got != want {
+					t.Errorf("Bazi = %v, want %v", resp.Data.BaziInfo.Bazi, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestGetPaipanResultLunarInput(t *testing.T) {
+	engine := NewEngine()
+
+	// 农历2023年闰二月初一即公历2023年3月22日
+	resp, err := engine.GetPaipanResult(context.Background(), bazi.Request{
+		Type: 0, Year: 2023, Month: 2, Day: 1, Leap: true, Hours: 12,
+	})
+	if err != nil {
+		t.Fatalf("GetPaipanResult() error = %v", err)
+	}
+	if resp.ErrCode != 0 {
+		t.Fatalf("GetPaipanResult() errcode = %d, errmsg = %s", resp.ErrCode, resp.ErrMsg)
+	}
+	if got := resp.Data.BaseInfo.Gongli; got != "2023年3月22日12时0分" {
+		t.Errorf("公历 = %s, want 2023年3月22日12时0分", got)
+	}
+	if got := resp.Data.BaseInfo.Nongli; got != "癸卯年 闰二月 初一日 午时" {
+		t.Errorf("农历 = %s, want 癸卯年 闰二月 初一日 午时", got)
+	}
+}
+
+func TestGetPaipanResultInvalidInput(t *testing.T) {
+	engine := NewEngine()
+	tests := []struct {
+		name string
+		req  bazi.Request
+	}{
+		{"不存在的农历闰月", bazi.Request{Type: 0, Year: 2024, Month: 2, Day: 1, Leap: true}},
+		{"无效历法类型", bazi.Request{Type: 2, Year: 2000, Month: 1, Day: 1}},
+		{"无效日期", bazi.Request{Type: 1, Year: 2023, Month: 2, Day: 30}},
+		{"超出年份范围", bazi.Request{Type: 1, Year: 1700, Month: 1, Day: 1}},
+		{"无效小时", bazi.Request{Type: 1, Year: 2000, Month: 1, Day: 1, Hours: 24}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := engine.GetPaipanResult(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("GetPaipanResult() error = %v", err)
+			}
+			if resp.ErrCode == 0 {
+				t.Error("应返回业务错误码")
+			}
+		})
+	}
+}
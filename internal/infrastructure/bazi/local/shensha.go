@@ -0,0 +1,82 @@
+package local
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// 以天干查地支的神煞表（按甲乙丙丁戊己庚辛壬癸排序）
+var (
+	tianYiTable   = [][]int{{1, 7}, {0, 8}, {11, 9}, {11, 9}, {1, 7}, {0, 8}, {1, 7}, {6, 2}, {3, 5}, {3, 5}}
+	taiJiTable    = [][]int{{0, 6}, {0, 6}, {3, 9}, {3, 9}, {4, 10, 1, 7}, {4, 10, 1, 7}, {2, 11}, {2, 11}, {5, 8}, {5, 8}}
+	wenChangTable = []int{5, 6, 8, 9, 8, 9, 11, 0, 2, 3}
+	luTable       = []int{2, 3, 5, 6, 5, 6, 8, 9, 11, 0}
+	yangRenTable  = []int{3, -1, 6, -1, 6, -1, 9, -1, 0, -1}
+)
+
+// sanHeStar 以三合局（申子辰、寅午戌、巳酉丑、亥卯未）查地支的神煞
+type sanHeStar struct {
+	name    string
+	targets [4]int // 依次对应水局、火局、金局、木局
+}
+
+var sanHeStars = []sanHeStar{
+	{"驿马", [4]int{2, 8, 11, 5}},
+	{"桃花", [4]int{9, 3, 6, 0}},
+	{"华盖", [4]int{4, 10, 1, 7}},
+	{"将星", [4]int{0, 6, 9, 3}},
+	{"劫煞", [4]int{5, 11, 2, 8}},
+	{"亡神", [4]int{11, 5, 8, 2}},
+}
+
+// sanHeGroup 返回地支所属三合局序号
+func sanHeGroup(branch int) int {
+	switch branch {
+	case 8, 0, 4:
+		return 0
+	case 2, 6, 10:
+		return 1
+	case 5, 9, 1:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// shensha 计算命盘中 branch 地支所带的神煞
+func (c *chart) shensha(branch int) string {
+	dayStem, yearStem := c.stem(2), c.stem(0)
+	dayBranch, yearBranch := c.branch(2), c.branch(0)
+
+	var stars []string
+	if slices.Contains(tianYiTable[dayStem], branch) || slices.Contains(tianYiTable[yearStem], branch) {
+		stars = append(stars, "天乙贵人")
+	}
+	if slices.Contains(taiJiTable[dayStem], branch) || slices.Contains(taiJiTable[yearStem], branch) {
+		stars = append(stars, "太极贵人")
+	}
+	if wenChangTable[dayStem] == branch || wenChangTable[yearStem] == branch {
+		stars = append(stars, "文昌贵人")
+	}
+	if luTable[dayStem] == branch {
+		stars = append(stars, "禄神")
+	}
+	if yangRenTable[dayStem] == branch {
+		stars = append(stars, "羊刃")
+	}
+	for _, star := range sanHeStars {
+		if star.targets[sanHeGroup(yearBranch)] == branch || star.targets[sanHeGroup(dayBranch)] == branch {
+			stars = append(stars, star.name)
+		}
+	}
+	if (15-yearBranch)%12 == branch {
+		stars = append(stars, "红鸾")
+	}
+	if strings.Contains(bazi.KongWang(c.pillars[2]), bazi.DiZhi[branch]) {
+		stars = append(stars, "空亡")
+	}
+
+	return strings.Join(stars, " ")
+}
@@ -0,0 +1,191 @@
+// Package provider 管理多个排盘提供方：按名称注册、按优先级组合，失败时依次回退到下一个提供方。
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// ErrUnknownProvider 未注册的提供方名称
+var ErrUnknownProvider = errors.New("未知排盘提供方")
+
+// Factory 创建排盘提供方
+type Factory func() (bazi.Service, error)
+
+// Registry 按名称注册的排盘提供方
+type Registry struct {
+	factories map[string]Factory
+}
+
+// NewRegistry 创建空的提供方注册表
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// Register 注册提供方，同名的提供方会被替换
+func (r *Registry) Register(name string, factory Factory) {
+	r.factories[name] = factory
+}
+
+// Names 返回已注册的提供方名称，按名称排序
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New 按名称创建提供方，名称未注册时返回 ErrUnknownProvider
+func (r *Registry) New(name string) (bazi.Service, error) {
+	factory, ok := r.factories[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s，可选 %s", ErrUnknownProvider, name, strings.Join(r.Names(), "、"))
+	}
+	service, err := factory()
+	if err != nil {
+		return nil, fmt.Errorf("创建排盘提供方 %s 失败: %w", name, err)
+	}
+	return service, nil
+}
+
+// Spec 提供方配置：名称与优先级，优先级数值越小越先调用
+type Spec struct {
+	Name     string
+	Priority int
+}
+
+// ParseSpecs 解析以逗号分隔的提供方配置，如 "yuanfenju:10,local:20"；
+// 省略优先级时按出现顺序依次为 1、2、3…
+func ParseSpecs(s string) ([]Spec, error) {
+	var specs []Spec
+	for i, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		spec := Spec{Name: item, Priority: i + 1}
+		if name, priority, ok := strings.Cut(item, ":"); ok {
+			p, err := strconv.Atoi(strings.TrimSpace(priority))
+			if err != nil {
+				return nil, fmt.Errorf("无效提供方优先级 %q", item)
+			}
+			spec = Spec{Name: strings.TrimSpace(name), Priority: p}
+		}
+		specs = append(specs, spec)
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("未配置排盘提供方")
+	}
+	return specs, nil
+}
+
+// Build 按配置创建各提供方，并组合为按优先级回退的排盘服务
+func (r *Registry) Build(specs []Spec) (*Fallback, error) {
+	providers := make([]Provider, 0, len(specs))
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		if seen[spec.Name] {
+			return nil, fmt.Errorf("排盘提供方 %s 重复配置", spec.Name)
+		}
+		seen[spec.Name] = true
+		service, err := r.New(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, Provider{Name: spec.Name, Priority: spec.Priority, Service: service})
+	}
+	return NewFallback(providers...), nil
+}
+
+// Provider 参与回退的一个排盘提供方
+type Provider struct {
+	Name     string
+	Priority int
+	Service  bazi.Service
+}
+
+// Fallback 实现了 bazi.Service 接口，按优先级依次调用各提供方，失败时回退到下一个，
+// 并在响应的 Provider 字段中记录实际给出结果的提供方。
+// 业务错误（输入有误）不回退，直接返回。
+type Fallback struct {
+	providers []Provider
+	logger    *log.Logger
+}
+
+// NewFallback 创建按优先级回退的排盘服务，优先级相同时保持传入顺序
+func NewFallback(providers ...Provider) *Fallback {
+	sorted := append([]Provider(nil), providers...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Priority < sorted[j].Priority })
+	return &Fallback{providers: sorted, logger: log.Default()}
+}
+
+// Providers 返回按调用顺序排列的提供方
+func (f *Fallback) Providers() []Provider {
+	return append([]Provider(nil), f.providers...)
+}
+
+// GetPaipanResult 依次调用各提供方，返回第一个成功的结果；全部失败时返回合并后的错误
+func (f *Fallback) GetPaipanResult(ctx context.Context, req bazi.Request) (*bazi.PaipanResponse, error) {
+	var errs []error
+	for i, p := range f.providers {
+		resp, err := p.Service.GetPaipanResult(ctx, req)
+		if err == nil && resp != nil {
+			if resp.Provider == "" {
+				resp.Provider = p.Name
+			}
+			if i > 0 {
+				f.logger.Printf("排盘提供方回退：由 %s 给出结果", p.Name)
+			}
+			return resp, nil
+		}
+
+		var providerErr *bazi.ProviderError
+		if errors.As(err, &providerErr) && providerErr.Kind == bazi.ErrorBusiness {
+			return nil, err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, errors.Join(append(errs, err)...)
+		}
+		if err == nil {
+			err = fmt.Errorf("未返回结果")
+		}
+		f.logger.Printf("排盘提供方 %s 调用失败: %v", p.Name, err)
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("未配置排盘提供方")
+	}
+	return nil, errors.Join(errs...)
+}
+
+// Status 并发探测各提供方的运行状态，按调用顺序返回；不支持探测的提供方标记为未知
+func (f *Fallback) Status(ctx context.Context) []bazi.ProviderStatus {
+	statuses := make([]bazi.ProviderStatus, len(f.providers))
+	var wg sync.WaitGroup
+	for i, p := range f.providers {
+		checker, ok := p.Service.(bazi.HealthChecker)
+		if !ok {
+			statuses[i] = bazi.ProviderStatus{Name: p.Name, Detail: "不支持健康检查"}
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := checker.CheckHealth(ctx)
+			status.Name = p.Name
+			statuses[i] = status
+		}()
+	}
+	wg.Wait()
+	return statuses
+}
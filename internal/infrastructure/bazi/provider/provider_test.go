@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+)
+
+// fakeService 返回固定结果并记录调用次数的排盘服务
+type fakeService struct {
+	resp  *bazi.PaipanResponse
+	err   error
+	calls int
+}
+
+func (s *fakeService) GetPaipanResult(ctx context.Context, req bazi.Request) (*bazi.PaipanResponse, error) {
+	s.calls++
+	return s.resp, s.err
+}
+
+// healthyService 支持健康检查的排盘服务
+type healthyService struct {
+	fakeService
+	status bazi.ProviderStatus
+}
+
+func (s *healthyService) CheckHealth(ctx context.Context) bazi.ProviderStatus {
+	return s.status
+}
+
+func newTestFallback(providers ...Provider) *Fallback {
+	f := NewFallback(providers...)
+	f.logger = log.New(io.Discard, "", 0)
+	return f
+}
+
+func TestFallback(t *testing.T) {
+	networkErr := &bazi.ProviderError{Kind: bazi.ErrorNetwork, Provider: "api", Err: errors.New("connection refused")}
+
+	t.Run("按优先级回退", func(t *testing.T) {
+		api := &fakeService{err: networkErr}
+		local := &fakeService{resp: &bazi.PaipanResponse{}}
+		f := newTestFallback(Provider{Name: "local", Priority: 20, Service: local}, Provider{Name: "api", Priority: 10, Service: api})
+
+		resp, err := f.GetPaipanResult(context.Background(), bazi.Request{})
+		if err != nil {
+			t.Fatalf("GetPaipanResult() error = %v", err)
+		}
+		if resp.Provider != "local" || api.calls != 1 || local.calls != 1 {
+			t.Errorf("提供方 = %q，调用次数 api=%d local=%d", resp.Provider, api.calls, local.calls)
+		}
+	})
+
+	t.Run("首选成功不回退", func(t *testing.T) {
+		api := &fakeService{resp: &bazi.PaipanResponse{}}
+		local := &fakeService{resp: &bazi.PaipanResponse{}}
+		f := newTestFallback(Provider{Name: "api", Priority: 1, Service: api}, Provider{Name: "local", Priority: 2, Service: local})
+
+		resp, err := f.GetPaipanResult(context.Background(), bazi.Request{})
+		if err != nil || resp.Provider != "api" || local.calls != 0 {
+			t.Errorf("GetPaipanResult() = %+v, %v，local 调用 %d 次", resp, err, local.calls)
+		}
+	})
+
+	t.Run("业务错误不回退", func(t *testing.T) {
+		businessErr := &bazi.ProviderError{Kind: bazi.ErrorBusiness, Provider: "api", ErrCode: 1, Message: "年份超出范围"}
+		local := &fakeService{resp: &bazi.PaipanResponse{}}
+		f := newTestFallback(Provider{Name: "api", Priority: 1, Service: &fakeService{err: businessErr}}, Provider{Name: "local", Priority: 2, Service: local})
+
+		if _, err := f.GetPaipanResult(context.Background(), bazi.Request{}); !errors.Is(err, businessErr) || local.calls != 0 {
+			t.Errorf("GetPaipanResult() error = %v，local 调用 %d 次", err, local.calls)
+		}
+	})
+
+	t.Run("全部失败", func(t *testing.T) {
+		fixtureErr := errors.New("没有对应的录制结果")
+		f := newTestFallback(Provider{Name: "api", Priority: 1, Service: &fakeService{err: networkErr}}, Provider{Name: "fixture", Priority: 2, Service: &fakeService{err: fixtureErr}})
+
+		_, err := f.GetPaipanResult(context.Background(), bazi.Request{})
+		var providerErr *bazi.ProviderError
+		if !errors.As(err, &providerErr) || !errors.Is(err, fixtureErr) {
+			t.Errorf("GetPaipanResult() error = %v，应包含各提供方的错误", err)
+		}
+	})
+}
+
+func TestParseSpecs(t *testing.T) {
+	specs, err := ParseSpecs("yuanfenju:10, local:5 ,fixture")
+	if err != nil {
+		t.Fatalf("ParseSpecs() error = %v", err)
+	}
+	want := []Spec{{Name: "yuanfenju", Priority: 10}, {Name: "local", Priority: 5}, {Name: "fixture", Priority: 3}}
+	if !reflect.DeepEqual(specs, want) {
+		t.Errorf("ParseSpecs() = %+v，期望 %+v", specs, want)
+	}
+
+	for _, s := range []string{"", " , ", "local:high"} {
+		if _, err := ParseSpecs(s); err == nil {
+			t.Errorf("ParseSpecs(%q) 应返回错误", s)
+		}
+	}
+}
+
+func TestRegistryBuild(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("api", func() (bazi.Service, error) { return &fakeService{}, nil })
+	registry.Register("local", func() (bazi.Service, error) { return &fakeService{}, nil })
+	registry.Register("broken", func() (bazi.Service, error) { return nil, errors.New("缺少配置") })
+
+	f, err := registry.Build([]Spec{{Name: "api", Priority: 2}, {Name: "local", Priority: 1}})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if providers := f.Providers(); len(providers) != 2 || providers[0].Name != "local" || providers[1].Name != "api" {
+		t.Errorf("Providers() = %+v，应按优先级排序", providers)
+	}
+
+	if _, err := registry.Build([]Spec{{Name: "remote"}}); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("未注册的提供方 error = %v", err)
+	}
+	if _, err := registry.Build([]Spec{{Name: "api"}, {Name: "api"}}); err == nil {
+		t.Error("重复配置的提供方应返回错误")
+	}
+	if _, err := registry.Build([]Spec{{Name: "broken"}}); err == nil {
+		t.Error("创建失败的提供方应返回错误")
+	}
+}
+
+func TestFallbackStatus(t *testing.T) {
+	api := &healthyService{status: bazi.ProviderStatus{Name: "ignored", Detail: "HTTP 503"}}
+	local := &healthyService{status: bazi.ProviderStatus{Reachable: true}}
+	f := newTestFallback(
+		Provider{Name: "local", Priority: 20, Service: local},
+		Provider{Name: "api", Priority: 10, Service: api},
+		Provider{Name: "other", Priority: 30, Service: &fakeService{}},
+	)
+
+	want := []bazi.ProviderStatus{
+		{Name: "api", Detail: "HTTP 503"},
+		{Name: "local", Reachable: true},
+		{Name: "other", Detail: "不支持健康检查"},
+	}
+	if got := f.Status(context.Background()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Status() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,472 @@
+// Package config 读取服务器配置：内置默认值、YAML 配置文件、环境变量与命令行参数依次覆盖，
+// 读取后统一校验，所有问题一并报告。
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/bazi"
+	baziInfra "github.com/justinwongcn/bazi-mcp/internal/infrastructure/bazi"
+	baziCache "github.com/justinwongcn/bazi-mcp/internal/infrastructure/bazi/cache"
+	baziProvider "github.com/justinwongcn/bazi-mcp/internal/infrastructure/bazi/provider"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 传输方式
+const (
+	TransportStdio = "stdio" // 标准输入输出，每个客户端启动一个进程
+	TransportSSE   = "sse"   // HTTP + Server-Sent Events
+	TransportHTTP  = "http"  // Streamable HTTP
+)
+
+// Config 服务器配置
+type Config struct {
+	Transport Transport  `yaml:"transport"`
+	Providers []Provider `yaml:"providers"` // 排盘提供方，按优先级回退
+	Compare   []string   `yaml:"compare"`   // 跨提供方比对的两个提供方，为空时取优先级最高的两个
+	API       API        `yaml:"api"`
+	Fixture   Fixture    `yaml:"fixture"`
+	Cache     Cache      `yaml:"cache"`
+	Defaults  Defaults   `yaml:"defaults"`
+	Analysis  Analysis   `yaml:"analysis"`
+	// HealthCheck 为 true 时启动前探测各排盘提供方，全部不可用则拒绝启动
+	HealthCheck bool `yaml:"health_check"`
+}
+
+// Transport 传输方式配置
+type Transport struct {
+	Mode     string `yaml:"mode"`      // stdio、sse 或 http
+	Addr     string `yaml:"addr"`      // HTTP 监听地址，仅 sse 与 http 使用
+	BasePath string `yaml:"base_path"` // 端点的公共路径前缀，例：/bazi
+}
+
+// Provider 排盘提供方及其优先级，优先级数值越小越先调用，为 0 时按书写顺序
+type Provider struct {
+	Name     string `yaml:"name"`
+	Priority int    `yaml:"priority"`
+}
+
+// API 缘分居排盘接口的调用配置
+type API struct {
+	Endpoint         string        `yaml:"endpoint"`          // 接口地址，可指向代理或模拟接口
+	Key              string        `yaml:"key"`               // 接口密钥
+	Timeout          time.Duration `yaml:"timeout"`           // 单次请求超时
+	Retries          int           `yaml:"retries"`           // 暂时性错误的重试次数，0 表示不重试
+	BreakerThreshold int           `yaml:"breaker_threshold"` // 触发熔断的连续失败次数，0 表示不熔断
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown"`  // 熔断时长
+}
+
+// Fixture 录制与回放配置
+type Fixture struct {
+	Dir       string `yaml:"dir"`        // fixture 提供方回放的录制结果目录
+	RecordDir string `yaml:"record_dir"` // 录制接口返回结果的目录，为空时不录制
+}
+
+// Cache 排盘缓存配置
+type Cache struct {
+	Size     int           `yaml:"size"`      // 内存缓存条数，0 表示关闭缓存
+	TTL      time.Duration `yaml:"ttl"`       // 缓存有效期
+	Dir      string        `yaml:"dir"`       // 磁盘缓存目录，为空时仅使用内存缓存
+	DiskSize int           `yaml:"disk_size"` // 磁盘缓存条数上限，0 表示不限制
+}
+
+// Defaults 排盘请求中未填写字段的默认值
+type Defaults struct {
+	Sect int    `yaml:"sect"` // 流派 1:晚子时日柱算明天 2:晚子时日柱算当天
+	Zhen int    `yaml:"zhen"` // 是否真太阳时 1:考虑 2:不考虑
+	Lang string `yaml:"lang"` // 语言 zh-cn、zh-tw
+}
+
+// Analysis 命局分析配置
+type Analysis struct {
+	Schools []string `yaml:"schools"` // 取用神的流派，按优先顺序排列：扶抑、调候、通关
+}
+
+// Default 返回内置默认配置，transport 为默认传输方式
+func Default(transport string) Config {
+	return Config{
+		Transport: Transport{Mode: transport, Addr: ":8080"},
+		Providers: []Provider{{Name: baziInfra.ProviderName, Priority: 1}},
+		API: API{
+			Endpoint:         baziInfra.APIEndpoint,
+			Timeout:          baziInfra.DefaultTimeout,
+			Retries:          baziInfra.DefaultMaxRetries,
+			BreakerThreshold: baziInfra.DefaultFailureThreshold,
+			BreakerCooldown:  baziInfra.DefaultCooldown,
+		},
+		Cache:    Cache{Size: baziCache.DefaultSize, TTL: baziCache.DefaultTTL},
+		Defaults: Defaults{Sect: 1, Zhen: 2, Lang: "zh-cn"},
+		Analysis: Analysis{Schools: []string{string(bazi.SchoolFuYi)}},
+	}
+}
+
+// ValidationError 配置校验失败，列出全部问题
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "配置无效：\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Load 依次以配置文件、环境变量与命令行参数覆盖 base 并校验。
+// 配置文件由 -config 参数或环境变量 BAZI_CONFIG 指定；校验失败时返回 *ValidationError。
+func Load(args []string, base Config, getenv func(string) string) (Config, error) {
+	cfg := base
+	l := &loader{getenv: getenv}
+
+	fs := flag.NewFlagSet("bazi-mcp", flag.ContinueOnError)
+	path := fs.String("config", getenv("BAZI_CONFIG"), "YAML 配置文件路径，环境变量 BAZI_CONFIG")
+	var f flagValues
+	f.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	if *path != "" {
+		if err := readFile(*path, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+	l.applyEnv(&cfg)
+	fs.Visit(func(fl *flag.Flag) { f.apply(fl.Name, &cfg, l) })
+
+	cfg.normalize()
+	l.problems = append(l.problems, cfg.validate()...)
+	if len(l.problems) > 0 {
+		return Config{}, &ValidationError{Problems: l.problems}
+	}
+	return cfg, nil
+}
+
+// readFile 读取 YAML 配置文件，未知字段视为错误以便发现拼写错误
+func readFile(path string, cfg *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	defer file.Close()
+	dec := yaml.NewDecoder(file)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("解析配置文件 %s 失败: %w", path, err)
+	}
+	return nil
+}
+
+// loader 读取环境变量与命令行参数，收集格式错误
+type loader struct {
+	getenv   func(string) string
+	problems []string
+}
+
+// applyEnv 以环境变量覆盖配置
+func (l *loader) applyEnv(cfg *Config) {
+	l.str("BAZI_TRANSPORT", &cfg.Transport.Mode)
+	l.str("BAZI_ADDR", &cfg.Transport.Addr)
+	l.str("BAZI_BASE_PATH", &cfg.Transport.BasePath)
+
+	if v := l.getenv("BAZI_PROVIDERS"); v != "" {
+		l.providers("BAZI_PROVIDERS", v, &cfg.Providers)
+	} else if v := l.getenv("BAZI_PROVIDER"); v != "" {
+		// 兼容旧的单一提供方配置：api 或 local
+		if v == "api" {
+			v = baziInfra.ProviderName
+		}
+		cfg.Providers = []Provider{{Name: v, Priority: 1}}
+	}
+	if v := l.getenv("BAZI_COMPARE"); v != "" {
+		cfg.Compare = splitList(v)
+	}
+
+	l.str("BAZI_API_ENDPOINT", &cfg.API.Endpoint)
+	l.str("API_KEY", &cfg.API.Key)
+	l.str("BAZI_API_KEY", &cfg.API.Key)
+	l.duration("BAZI_API_TIMEOUT", l.getenv("BAZI_API_TIMEOUT"), &cfg.API.Timeout)
+	l.integer("BAZI_API_RETRIES", l.getenv("BAZI_API_RETRIES"), &cfg.API.Retries)
+	l.integer("BAZI_API_BREAKER_THRESHOLD", l.getenv("BAZI_API_BREAKER_THRESHOLD"), &cfg.API.BreakerThreshold)
+	l.duration("BAZI_API_BREAKER_COOLDOWN", l.getenv("BAZI_API_BREAKER_COOLDOWN"), &cfg.API.BreakerCooldown)
+
+	l.str("BAZI_FIXTURE_DIR", &cfg.Fixture.Dir)
+	l.str("BAZI_RECORD_DIR", &cfg.Fixture.RecordDir)
+
+	l.integer("BAZI_CACHE_SIZE", l.getenv("BAZI_CACHE_SIZE"), &cfg.Cache.Size)
+	l.duration("BAZI_CACHE_TTL", l.getenv("BAZI_CACHE_TTL"), &cfg.Cache.TTL)
+	l.str("BAZI_CACHE_DIR", &cfg.Cache.Dir)
+	l.integer("BAZI_CACHE_DISK_SIZE", l.getenv("BAZI_CACHE_DISK_SIZE"), &cfg.Cache.DiskSize)
+
+	l.integer("BAZI_SECT", l.getenv("BAZI_SECT"), &cfg.Defaults.Sect)
+	l.integer("BAZI_ZHEN", l.getenv("BAZI_ZHEN"), &cfg.Defaults.Zhen)
+	l.str("BAZI_LANG", &cfg.Defaults.Lang)
+
+	if v := l.getenv("BAZI_SCHOOLS"); v != "" {
+		cfg.Analysis.Schools = splitList(v)
+	}
+
+	l.boolean("BAZI_HEALTH_CHECK", l.getenv("BAZI_HEALTH_CHECK"), &cfg.HealthCheck)
+}
+
+// str 环境变量非空时覆盖字符串配置
+func (l *loader) str(key string, target *string) {
+	if v := l.getenv(key); v != "" {
+		*target = v
+	}
+}
+
+// integer 解析非空的整数配置
+func (l *loader) integer(source, value string, target *int) {
+	if value == "" {
+		return
+	}
+	v, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		l.problems = append(l.problems, fmt.Sprintf("%s=%q 不是整数", source, value))
+		return
+	}
+	*target = v
+}
+
+// boolean 解析非空的布尔配置
+func (l *loader) boolean(source, value string, target *bool) {
+	if value == "" {
+		return
+	}
+	v, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		l.problems = append(l.problems, fmt.Sprintf("%s=%q 不是布尔值，例：true、false", source, value))
+		return
+	}
+	*target = v
+}
+
+// duration 解析非空的时长配置
+func (l *loader) duration(source, value string, target *time.Duration) {
+	if value == "" {
+		return
+	}
+	v, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		l.problems = append(l.problems, fmt.Sprintf("%s=%q 不是有效时长，例：10s、24h", source, value))
+		return
+	}
+	*target = v
+}
+
+// providers 解析以逗号分隔的提供方配置，例："yuanfenju:10,local:20"，省略优先级时按书写顺序
+func (l *loader) providers(source, value string, target *[]Provider) {
+	specs, err := baziProvider.ParseSpecs(value)
+	if err != nil {
+		l.problems = append(l.problems, fmt.Sprintf("%s=%q: %v", source, value, err))
+		return
+	}
+	providers := make([]Provider, 0, len(specs))
+	for _, spec := range specs {
+		providers = append(providers, Provider{Name: spec.Name, Priority: spec.Priority})
+	}
+	*target = providers
+}
+
+// splitList 按逗号拆分并去除空白与空项
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// flagValues 命令行参数，只有显式指定的参数才覆盖配置
+type flagValues struct {
+	transport, addr, basePath, providers, apiEndpoint, apiTimeout string
+	cacheSize, cacheTTL, cacheDir, sect, zhen, lang               string
+	healthCheck                                                   bool
+}
+
+func (f *flagValues) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.transport, "transport", "", "传输方式：stdio、sse 或 http（Streamable HTTP），环境变量 BAZI_TRANSPORT")
+	fs.StringVar(&f.addr, "addr", "", "sse 与 http 模式的监听地址，默认 :8080，环境变量 BAZI_ADDR")
+	fs.StringVar(&f.basePath, "base-path", "", "sse 与 http 模式的端点路径前缀，例：/bazi，环境变量 BAZI_BASE_PATH")
+	fs.StringVar(&f.providers, "providers", "", "排盘提供方及优先级，例：yuanfenju:10,local:20，环境变量 BAZI_PROVIDERS")
+	fs.StringVar(&f.apiEndpoint, "api-endpoint", "", "排盘接口地址，环境变量 BAZI_API_ENDPOINT")
+	fs.StringVar(&f.apiTimeout, "api-timeout", "", "排盘接口单次请求超时，例：10s，环境变量 BAZI_API_TIMEOUT")
+	fs.StringVar(&f.cacheSize, "cache-size", "", "内存缓存条数，0 表示关闭缓存，环境变量 BAZI_CACHE_SIZE")
+	fs.StringVar(&f.cacheTTL, "cache-ttl", "", "缓存有效期，例：24h，环境变量 BAZI_CACHE_TTL")
+	fs.StringVar(&f.cacheDir, "cache-dir", "", "磁盘缓存目录，环境变量 BAZI_CACHE_DIR")
+	fs.StringVar(&f.sect, "sect", "", "默认流派 1 或 2，环境变量 BAZI_SECT")
+	fs.StringVar(&f.zhen, "zhen", "", "默认是否真太阳时 1 或 2，环境变量 BAZI_ZHEN")
+	fs.StringVar(&f.lang, "lang", "", "默认语言 zh-cn 或 zh-tw，环境变量 BAZI_LANG")
+	fs.BoolVar(&f.healthCheck, "health-check", false, "启动前探测排盘提供方，全部不可用时拒绝启动，环境变量 BAZI_HEALTH_CHECK")
+}
+
+// apply 以显式指定的命令行参数覆盖配置
+func (f *flagValues) apply(name string, cfg *Config, l *loader) {
+	source := "-" + name
+	switch name {
+	case "transport":
+		cfg.Transport.Mode = f.transport
+	case "addr":
+		cfg.Transport.Addr = f.addr
+	case "base-path":
+		cfg.Transport.BasePath = f.basePath
+	case "providers":
+		l.providers(source, f.providers, &cfg.Providers)
+	case "api-endpoint":
+		cfg.API.Endpoint = f.apiEndpoint
+	case "api-timeout":
+		l.duration(source, f.apiTimeout, &cfg.API.Timeout)
+	case "cache-size":
+		l.integer(source, f.cacheSize, &cfg.Cache.Size)
+	case "cache-ttl":
+		l.duration(source, f.cacheTTL, &cfg.Cache.TTL)
+	case "cache-dir":
+		cfg.Cache.Dir = f.cacheDir
+	case "sect":
+		l.integer(source, f.sect, &cfg.Defaults.Sect)
+	case "zhen":
+		l.integer(source, f.zhen, &cfg.Defaults.Zhen)
+	case "lang":
+		cfg.Defaults.Lang = f.lang
+	case "health-check":
+		cfg.HealthCheck = f.healthCheck
+	}
+}
+
+// normalize 规范传输方式、路径前缀与提供方优先级
+func (c *Config) normalize() {
+	switch strings.ToLower(strings.TrimSpace(c.Transport.Mode)) {
+	case "", TransportStdio:
+		c.Transport.Mode = TransportStdio
+	case TransportSSE:
+		c.Transport.Mode = TransportSSE
+	case TransportHTTP, "streamable-http", "streamable_http":
+		c.Transport.Mode = TransportHTTP
+	}
+	c.Transport.BasePath = normalizeBasePath(c.Transport.BasePath)
+	for i := range c.Providers {
+		c.Providers[i].Name = strings.TrimSpace(c.Providers[i].Name)
+		if c.Providers[i].Priority == 0 {
+			c.Providers[i].Priority = i + 1
+		}
+	}
+	c.API.Endpoint = strings.TrimRight(strings.TrimSpace(c.API.Endpoint), "/")
+	c.API.Key = strings.TrimSpace(c.API.Key)
+	c.Defaults.Lang = strings.ToLower(strings.TrimSpace(c.Defaults.Lang))
+	for i, name := range c.Analysis.Schools {
+		if school, err := bazi.ParseSchool(name); err == nil {
+			c.Analysis.Schools[i] = string(school)
+		}
+	}
+}
+
+// normalizeBasePath 将路径前缀规范为以 / 开头、不以 / 结尾的形式，根路径返回空字符串
+func normalizeBasePath(basePath string) string {
+	basePath = strings.Trim(strings.TrimSpace(basePath), "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
+// validate 校验配置，返回全部问题
+func (c *Config) validate() []string {
+	var problems []string
+	add := func(format string, args ...any) { problems = append(problems, fmt.Sprintf(format, args...)) }
+
+	switch c.Transport.Mode {
+	case TransportStdio:
+	case TransportSSE, TransportHTTP:
+		if c.Transport.Addr == "" {
+			add("transport.addr: %s 模式需要监听地址，例：:8080", c.Transport.Mode)
+		}
+	default:
+		add("transport.mode: 无效传输方式 %q，可选 stdio、sse、http", c.Transport.Mode)
+	}
+
+	if len(c.Providers) == 0 {
+		add("providers: 未配置排盘提供方")
+	}
+	seen := make(map[string]bool)
+	for i, p := range c.Providers {
+		switch {
+		case p.Name == "":
+			add("providers[%d].name: 不能为空", i)
+		case seen[p.Name]:
+			add("providers[%d].name: 排盘提供方 %s 重复配置", i, p.Name)
+		}
+		seen[p.Name] = true
+	}
+	if len(c.Compare) != 0 && len(c.Compare) != 2 {
+		add("compare: 需要两个排盘提供方，例：[yuanfenju, local]")
+	}
+
+	if c.API.Key == "" && c.usesProvider(baziInfra.ProviderName) {
+		add("api.key: 使用 %s 提供方需要接口密钥，可通过环境变量 API_KEY 或配置文件提供", baziInfra.ProviderName)
+	}
+	if u, err := url.Parse(c.API.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		add("api.endpoint: 无效接口地址 %q，应为 http 或 https 地址", c.API.Endpoint)
+	}
+	if c.API.Timeout <= 0 {
+		add("api.timeout: 应大于 0，例：10s")
+	}
+	if c.API.Retries < 0 {
+		add("api.retries: 不能为负数")
+	}
+	if c.API.BreakerThreshold < 0 {
+		add("api.breaker_threshold: 不能为负数")
+	}
+	if c.API.BreakerCooldown <= 0 {
+		add("api.breaker_cooldown: 应大于 0，例：30s")
+	}
+
+	if c.Cache.Size < 0 {
+		add("cache.size: 不能为负数")
+	}
+	if c.Cache.TTL <= 0 {
+		add("cache.ttl: 应大于 0，例：24h")
+	}
+	if c.Cache.DiskSize < 0 {
+		add("cache.disk_size: 不能为负数")
+	}
+
+	if c.Defaults.Sect != 1 && c.Defaults.Sect != 2 {
+		add("defaults.sect: 应为 1 或 2")
+	}
+	if c.Defaults.Zhen != 1 && c.Defaults.Zhen != 2 {
+		add("defaults.zhen: 应为 1 或 2")
+	}
+	if c.Defaults.Lang != "zh-cn" && c.Defaults.Lang != "zh-tw" {
+		add("defaults.lang: 应为 zh-cn 或 zh-tw")
+	}
+	for i, name := range c.Analysis.Schools {
+		if _, err := bazi.ParseSchool(name); err != nil {
+			add("analysis.schools[%d]: %v", i, err)
+		}
+	}
+	return problems
+}
+
+// usesProvider 判断排盘或比对是否使用指定提供方
+func (c *Config) usesProvider(name string) bool {
+	for _, p := range c.Providers {
+		if p.Name == name {
+			return true
+		}
+	}
+	for _, n := range c.Compare {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// env 以 map 模拟环境变量
+func env(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func TestLoadDefault(t *testing.T) {
+	cfg, err := Load(nil, Default("stdio"), env(map[string]string{"API_KEY": "key"}))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := Default("stdio")
+	want.API.Key = "key"
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load() = %+v，应与默认配置相同", cfg)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bazi.yaml")
+	file := `
+transport:
+  mode: http
+  addr: ":9000"
+  base_path: bazi/
+providers:
+  - name: yuanfenju
+  - name: local
+api:
+  endpoint: http://proxy.internal/v1/Bazi/
+  key: file-key
+  timeout: 5s
+  retries: 0
+cache:
+  size: 100
+  ttl: 1h
+defaults:
+  sect: 2
+  lang: zh-tw
+`
+	if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load([]string{"-config", path, "-addr", ":9100", "-cache-size", "0"}, Default("stdio"), env(map[string]string{
+		"BAZI_ADDR":        ":9001",
+		"API_KEY":          "env-key",
+		"BAZI_API_TIMEOUT": "3s",
+		"BAZI_ZHEN":        "1",
+	}))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := Default("stdio")
+	want.Transport = Transport{Mode: TransportHTTP, Addr: ":9100", BasePath: "/bazi"}
+	want.Providers = []Provider{{Name: "yuanfenju", Priority: 1}, {Name: "local", Priority: 2}}
+	want.API.Endpoint = "http://proxy.internal/v1/Bazi"
+	want.API.Key = "env-key"
+	want.API.Timeout = 3 * time.Second
+	want.API.Retries = 0
+	want.Cache = Cache{Size: 0, TTL: time.Hour}
+	want.Defaults = Defaults{Sect: 2, Zhen: 1, Lang: "zh-tw"}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load() =\n%+v\n期望\n%+v", cfg, want)
+	}
+}
+
+func TestLoadProviders(t *testing.T) {
+	cfg, err := Load(nil, Default("stdio"), env(map[string]string{"BAZI_PROVIDER": "local"}))
+	if err != nil || !reflect.DeepEqual(cfg.Providers, []Provider{{Name: "local", Priority: 1}}) {
+		t.Errorf("BAZI_PROVIDER=local: %+v, %v", cfg.Providers, err)
+	}
+
+	cfg, err = Load([]string{"-providers", "fixture"}, Default("stdio"), env(map[string]string{
+		"BAZI_PROVIDERS": "yuanfenju:10,local:20",
+		"BAZI_PROVIDER":  "local",
+		"BAZI_COMPARE":   "yuanfenju, local",
+		"API_KEY":        "key",
+	}))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Providers, []Provider{{Name: "fixture", Priority: 1}}) || !reflect.DeepEqual(cfg.Compare, []string{"yuanfenju", "local"}) {
+		t.Errorf("提供方 = %+v，比对 = %q", cfg.Providers, cfg.Compare)
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	_, err := Load([]string{"-transport", "grpc", "-sect", "3"}, Default("stdio"), env(map[string]string{
+		"BAZI_API_ENDPOINT": "proxy.internal",
+		"BAZI_API_RETRIES":  "two",
+		"BAZI_CACHE_TTL":    "0s",
+		"BAZI_PROVIDERS":    "local,local",
+		"BAZI_COMPARE":      "local",
+	}))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Load() error = %v，期望 *ValidationError", err)
+	}
+	for _, want := range []string{"BAZI_API_RETRIES", "transport.mode", "providers[1].name", "compare", "api.endpoint", "cache.ttl", "defaults.sect"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("错误信息应包含 %s:\n%v", want, err)
+		}
+	}
+	if len(validationErr.Problems) != 7 {
+		t.Errorf("问题数 = %d:\n%v", len(validationErr.Problems), err)
+	}
+
+	if _, err := Load([]string{"-transport", "sse", "-addr", ""}, Default("stdio"), env(nil)); err == nil || !strings.Contains(err.Error(), "transport.addr") {
+		t.Errorf("sse 模式未配置监听地址 error = %v", err)
+	}
+}
+
+func TestLoadAPIKey(t *testing.T) {
+	if _, err := Load(nil, Default("stdio"), env(nil)); err == nil || !strings.Contains(err.Error(), "api.key") {
+		t.Errorf("未配置接口密钥 error = %v", err)
+	}
+	if _, err := Load(nil, Default("stdio"), env(map[string]string{"BAZI_PROVIDERS": "local", "BAZI_COMPARE": "local,yuanfenju"})); err == nil || !strings.Contains(err.Error(), "api.key") {
+		t.Errorf("比对使用 yuanfenju 且未配置接口密钥 error = %v", err)
+	}
+	cfg, err := Load([]string{"-health-check"}, Default("stdio"), env(map[string]string{"BAZI_PROVIDER": "local"}))
+	if err != nil {
+		t.Fatalf("不使用 yuanfenju 时无需接口密钥 error = %v", err)
+	}
+	if !cfg.HealthCheck {
+		t.Error("-health-check 未生效")
+	}
+	if _, err := Load(nil, Default("stdio"), env(map[string]string{"BAZI_PROVIDER": "local", "BAZI_HEALTH_CHECK": "sometimes"})); err == nil || !strings.Contains(err.Error(), "BAZI_HEALTH_CHECK") {
+		t.Errorf("无效布尔值 error = %v", err)
+	}
+}
+
+func TestLoadSchools(t *testing.T) {
+	cfg, err := Load(nil, Default("stdio"), env(map[string]string{"BAZI_PROVIDER": "local", "BAZI_SCHOOLS": "tiaohou, 扶抑"}))
+	if err != nil || !reflect.DeepEqual(cfg.Analysis.Schools, []string{"调候", "扶抑"}) {
+		t.Errorf("取用流派 = %q, %v", cfg.Analysis.Schools, err)
+	}
+	if _, err := Load(nil, Default("stdio"), env(map[string]string{"BAZI_PROVIDER": "local", "BAZI_SCHOOLS": "格局"})); err == nil || !strings.Contains(err.Error(), "analysis.schools[0]") {
+		t.Errorf("未知流派 error = %v", err)
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bazi.yaml")
+	if err := os.WriteFile(path, []byte("api:\n  timout: 5s\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(nil, Default("stdio"), env(map[string]string{"BAZI_CONFIG": path})); err == nil || !strings.Contains(err.Error(), "timout") {
+		t.Errorf("未知字段 error = %v", err)
+	}
+	if _, err := Load([]string{"-config", filepath.Join(dir, "missing.yaml")}, Default("stdio"), env(nil)); err == nil {
+		t.Error("配置文件不存在时应返回错误")
+	}
+}
@@ -0,0 +1,99 @@
+package location
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/location"
+)
+
+// provincesJSON 内嵌的地名录数据集：省级行政区及其下辖市县的行政区划代码与经纬度，
+// 未收录坐标的地点省略 longitude/latitude 字段，aliases 为别名到别名类型的映射
+//
+//go:embed provinces.json
+var provincesJSON []byte
+
+// provinceRecord 对应数据集中的一个省级行政区
+type provinceRecord struct {
+	Name   string       `json:"name"`
+	Code   string       `json:"code"`
+	Seat   string       `json:"seat"`
+	Cities []cityRecord `json:"cities"`
+}
+
+// cityRecord 对应数据集中的一个市县
+type cityRecord struct {
+	Name      string            `json:"name"`
+	Code      string            `json:"code"`
+	Longitude *float64          `json:"longitude,omitempty"`
+	Latitude  *float64          `json:"latitude,omitempty"`
+	Aliases   map[string]string `json:"aliases,omitempty"`
+}
+
+// aliasKinds 数据集中的别名类型
+var aliasKinds = map[string]location.AliasKind{
+	"modern":     location.AliasModern,
+	"historical": location.AliasHistorical,
+	"district":   location.AliasDistrict,
+	"prefecture": location.AliasPrefecture,
+	"correction": location.AliasCorrection,
+}
+
+// LoadGazetteer 从内嵌数据集加载地名录
+func LoadGazetteer() (*location.Gazetteer, error) {
+	return parseGazetteer(provincesJSON)
+}
+
+// parseGazetteer 解析地名录数据集
+func parseGazetteer(data []byte) (*location.Gazetteer, error) {
+	var records []provinceRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("解析地名录数据失败: %w", err)
+	}
+
+	divisions := make([]location.Division, 0, len(records))
+	for _, r := range records {
+		division := location.Division{Name: r.Name, Code: r.Code, Seat: r.Seat}
+		for _, c := range r.Cities {
+			if !isDivisionCode(c.Code) {
+				return nil, fmt.Errorf("解析地名录数据失败: %s %s 的行政区划代码 %q 无效", r.Name, c.Name, c.Code)
+			}
+			place := location.Place{City: c.Name, Code: c.Code}
+			if c.Longitude != nil && c.Latitude != nil {
+				place.Longitude, place.Latitude = *c.Longitude, *c.Latitude
+			} else {
+				place.Approximate = true
+			}
+			division.Places = append(division.Places, place)
+
+			for name, kind := range c.Aliases {
+				aliasKind, ok := aliasKinds[kind]
+				if !ok {
+					return nil, fmt.Errorf("解析地名录数据失败: %s %s 的别名 %s 类型 %q 无效", r.Name, c.Name, name, kind)
+				}
+				division.Aliases = append(division.Aliases, location.Alias{Name: name, City: c.Name, Kind: aliasKind})
+			}
+		}
+		divisions = append(divisions, division)
+	}
+
+	gazetteer, err := location.NewGazetteer(divisions)
+	if err != nil {
+		return nil, fmt.Errorf("构建地名录失败: %w", err)
+	}
+	return gazetteer, nil
+}
+
+// isDivisionCode 判断是否为六位数字的行政区划代码
+func isDivisionCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package location
+
+import (
+	"math"
+	"testing"
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/location"
+)
+
+func TestLoadGazetteer(t *testing.T) {
+	g, err := LoadGazetteer()
+	if err != nil {
+		t.Fatalf("LoadGazetteer() error = %v", err)
+	}
+
+	// 数据集须覆盖领域层的全部省份与市县，且每个地点都带有行政区划代码
+	for _, province := range location.Provinces {
+		if _, ok := g.Seat(province); !ok {
+			t.Errorf("缺少省份 %s", province)
+			continue
+		}
+		for _, city := range location.Cities[province] {
+			place, ok := g.Lookup(province, city)
+			if !ok {
+				t.Errorf("缺少 %s %s", province, city)
+				continue
+			}
+			if !isDivisionCode(place.Code) {
+				t.Errorf("%s %s 的行政区划代码 %q 无效", province, city, place.Code)
+			}
+		}
+	}
+
+	tests := []struct {
+		province, city string
+		lon, lat       float64
+	}{
+		{"北京市", "北京", 116.41, 39.90},
+		{"新疆维吾尔自治区", "哈密", 93.51, 42.83},
+		{"黑龙江省", "漠河", 122.53, 52.97},
+		// 以下条目曾将“度.分”误记为小数度或度数错位，参考值为市县人民政府驻地
+		{"新疆维吾尔自治区", "咯什", 75.99, 39.47},
+		{"港澳台", "台北", 121.52, 25.05},
+		{"江西省", "上饶", 117.97, 28.45},
+		{"江西省", "赣州", 114.93, 25.83},
+		{"浙江省", "乐清", 120.96, 28.12},
+		{"山东省", "昌邑", 119.40, 36.86},
+		{"福建省", "崇安", 118.03, 27.76},
+	}
+	for _, tt := range tests {
+		place, ok := g.Lookup(tt.province, tt.city)
+		if !ok || place.Approximate || math.Abs(place.Longitude-tt.lon) > 0.1 || math.Abs(place.Latitude-tt.lat) > 0.1 {
+			t.Errorf("Lookup(%s, %s) = %+v, want about (%v, %v)", tt.province, tt.city, place, tt.lon, tt.lat)
+		}
+	}
+
+	aliases := []struct {
+		input, province, city string
+		kind                  location.AliasKind
+	}{
+		{"通州", "北京市", "通县", location.AliasModern},
+		{"海淀区", "北京市", "北京", location.AliasDistrict},
+		{"浦东新区", "上海市", "川沙", location.AliasModern},
+		{"酉阳", "重庆市", "西阳", location.AliasCorrection},
+	}
+	codes := []struct {
+		province, city, code string
+	}{
+		{"北京市", "北京", "110000"},
+		{"北京市", "通县", "110112"},
+		{"上海市", "川沙", "310115"},
+		{"江苏省", "吴县", "320506"},
+		{"安徽省", "屯溪", "341002"},
+		{"浙江省", "乐清", "330382"},
+		{"山东省", "长岛", "370614"},
+		{"新疆维吾尔自治区", "咯什", "653101"},
+		{"云南省", "碧江", "533323"},
+		{"港澳台", "台北", "710000"},
+		{"港澳台", "香港", "810000"},
+	}
+	for _, tt := range codes {
+		if place, ok := g.Lookup(tt.province, tt.city); !ok || place.Code != tt.code {
+			t.Errorf("Lookup(%s, %s).Code = %q, want %q", tt.province, tt.city, place.Code, tt.code)
+		}
+	}
+
+	for _, tt := range aliases {
+		m, ok := g.MatchCity(tt.input, tt.province)
+		if !ok || m.Reason != location.MatchAlias || m.Place.City != tt.city || m.Alias.Kind != tt.kind {
+			t.Errorf("MatchCity(%s, %s) = %+v, want %s (%v)", tt.input, tt.province, m, tt.city, tt.kind)
+		}
+	}
+}
+
+func TestParseGazetteerInvalid(t *testing.T) {
+	if _, err := parseGazetteer([]byte(`{`)); err == nil {
+		t.Error("无效 JSON 应返回错误")
+	}
+	if _, err := parseGazetteer([]byte(`[{"name": "北京市", "code": "110000", "seat": "北京", "cities": [{"name": "平谷", "code": "110117"}]}]`)); err == nil {
+		t.Error("缺少省会坐标应返回错误")
+	}
+	if _, err := parseGazetteer([]byte(`[{"name": "北京市", "code": "110000", "seat": "北京", "cities": [{"name": "北京", "code": "110000", "longitude": 116.4, "latitude": 39.9, "aliases": {"海淀": "unknown"}}]}]`)); err == nil {
+		t.Error("无效别名类型应返回错误")
+	}
+	if _, err := parseGazetteer([]byte(`[{"name": "北京市", "code": "110000", "seat": "北京", "cities": [{"name": "北京", "longitude": 116.4, "latitude": 39.9}]}]`)); err == nil {
+		t.Error("缺少行政区划代码应返回错误")
+	}
+}
@@ -0,0 +1,34 @@
+package location
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	_ "time/tzdata" // 内嵌 IANA 时区数据，避免依赖运行环境的 zoneinfo
+
+	"github.com/justinwongcn/bazi-mcp/internal/domain/location"
+)
+
+// worldCitiesJSON 内嵌的境外城市数据集：中英文名称、国家、经纬度与 IANA 时区
+//
+//go:embed world_cities.json
+var worldCitiesJSON []byte
+
+// LoadWorldGazetteer 从内嵌数据集加载境外城市地名录
+func LoadWorldGazetteer() (*location.WorldGazetteer, error) {
+	return parseWorldGazetteer(worldCitiesJSON)
+}
+
+// parseWorldGazetteer 解析境外城市数据集
+func parseWorldGazetteer(data []byte) (*location.WorldGazetteer, error) {
+	var cities []location.WorldCity
+	if err := json.Unmarshal(data, &cities); err != nil {
+		return nil, fmt.Errorf("解析境外城市数据失败: %w", err)
+	}
+
+	gazetteer, err := location.NewWorldGazetteer(cities)
+	if err != nil {
+		return nil, fmt.Errorf("构建境外城市地名录失败: %w", err)
+	}
+	return gazetteer, nil
+}
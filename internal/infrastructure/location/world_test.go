@@ -0,0 +1,33 @@
+package location
+
+import (
+	"testing"
+)
+
+func TestLoadWorldGazetteer(t *testing.T) {
+	g, err := LoadWorldGazetteer()
+	if err != nil {
+		t.Fatalf("LoadWorldGazetteer() error = %v", err)
+	}
+
+	tests := []struct {
+		input, name, timezone string
+	}{
+		{"纽约", "纽约", "America/New_York"},
+		{"new york", "纽约", "America/New_York"},
+		{"伦敦市", "伦敦", "Europe/London"},
+		{"Sydney", "悉尼", "Australia/Sydney"},
+	}
+	for _, tt := range tests {
+		city, ok := g.Lookup(tt.input)
+		if !ok || city.Name != tt.name || city.TimeZone != tt.timezone {
+			t.Errorf("Lookup(%s) = %+v, want %s (%s)", tt.input, city, tt.name, tt.timezone)
+		}
+	}
+}
+
+func TestParseWorldGazetteerInvalid(t *testing.T) {
+	if _, err := parseWorldGazetteer([]byte(`[{"name": "纽约", "english_name": "New York", "country": "美国", "longitude": -74, "latitude": 40.7, "timezone": "America/Gotham"}]`)); err == nil {
+		t.Error("无效时区应返回错误")
+	}
+}